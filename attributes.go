@@ -0,0 +1,82 @@
+package goui
+
+import (
+	"html/template"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Validation and rendering of HTML attributes.
+//
+
+// Boolean attributes are true when present, and are rendered by name only, e.g. <input disabled>.
+var booleanAttributes = map[string]bool{
+	"allowfullscreen": true, "async": true, "autofocus": true, "autoplay": true, "checked": true,
+	"controls": true, "default": true, "defer": true, "disabled": true, "formnovalidate": true,
+	"hidden": true, "inert": true, "ismap": true, "itemscope": true, "loop": true, "multiple": true,
+	"muted": true, "nomodule": true, "novalidate": true, "open": true, "playsinline": true,
+	"readonly": true, "required": true, "reversed": true, "selected": true,
+}
+
+// Reports whether the name is a valid HTML attribute name. Names must be non-empty, and may not
+// contain whitespace, control characters, quotes, ">", "/", "=" or non-characters.
+func ValidAttributeName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r), unicode.IsControl(r), r == unicode.ReplacementChar:
+			return false
+		case r == '"', r == '\'', r == '>', r == '/', r == '=', r == '<':
+			return false
+		case r >= 0xFDD0 && r <= 0xFDEF, r&0xFFFE == 0xFFFE:
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether the attribute is a boolean attribute, e.g. disabled, checked or required.
+func IsBooleanAttribute(name string) bool {
+	return booleanAttributes[strings.ToLower(name)]
+}
+
+// Boolean attributes are set with an empty value, the attribute name, or "true". The value "false"
+// leaves the attribute out.
+func booleanAttributeSet(value string) bool {
+	return strings.ToLower(strings.TrimSpace(value)) != "false"
+}
+
+// Write the attributes in name order, so the output is the same for every render. Values are
+// escaped, boolean attributes use the short form, and invalid names are left out.
+// Names in skip are also left out, e.g. when the caller has already written them.
+func (attr AttributeMap) render(ew *errWriter, skip ...string) {
+	keys := make([]string, 0, len(attr))
+	for k := range attr {
+		if !ValidAttributeName(k) {
+			log.Printf("goui.AttributeMap: invalid attribute name %q", k)
+			continue
+		}
+		if containsString(skip, k) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if IsBooleanAttribute(k) {
+			if booleanAttributeSet(attr[k]) {
+				ew.write(" " + k)
+			}
+			continue
+		}
+		writeAttribute(ew, k, attr[k])
+	}
+}
+
+func writeAttribute(ew *errWriter, name, value string) {
+	ew.write(" " + name + "=\"" + template.HTMLEscapeString(value) + "\"")
+}
@@ -0,0 +1,44 @@
+package goui
+
+import (
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestAttributeMap_String(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		a := AttributeMap{"title": `say "hi" <script>`, "href": "/a?x=1&y=2", "data-z": "z"}
+		x := string(a.String())
+		gotestutil.AssertStringsEqual(t, x,
+			` data-z="z" href="/a?x=1&amp;y=2" title="say &#34;hi&#34; &lt;script&gt;"`,
+			"Unexpected attributes. Actual: %s.", x)
+	})
+
+	t.Run("A2", func(t *testing.T) {
+		a := AttributeMap{"disabled": "", "checked": "checked", "required": "true", "readonly": "false"}
+		x := string(a.String())
+		gotestutil.AssertStringsEqual(t, x, ` checked disabled required`, "Unexpected boolean attributes. Actual: %s.", x)
+	})
+
+	t.Run("B1", func(t *testing.T) {
+		a := AttributeMap{`onclick="x"`: "y", "a b": "c", "ok": "1"}
+		x := string(a.String())
+		gotestutil.AssertStringsEqual(t, x, ` ok="1"`, "Expected invalid names left out. Actual: %s.", x)
+	})
+}
+
+func TestValidAttributeName(t *testing.T) {
+	for _, v := range []string{"href", "data-toggle", "aria-label", "xml:lang", "@click"} {
+		gotestutil.AssertTrue(t, ValidAttributeName(v), "Expected valid attribute name %q.", v)
+	}
+	for _, v := range []string{"", "a b", "a=b", "a\"", "a>", "a/", "a\x00"} {
+		gotestutil.AssertFalse(t, ValidAttributeName(v), "Expected invalid attribute name %q.", v)
+	}
+}
+
+func TestUIObject_AddAttributeInvalid(t *testing.T) {
+	uio := NewElement("div", "d1", "", "")
+	uio.AddAttribute("bad name", "x")
+	gotestutil.AssertEqual(t, len(uio.attrs), 0, "Expected invalid attribute not added.")
+}
@@ -0,0 +1,121 @@
+package goui
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+// Breadcrumb trails from a navigation tree. The trail is rendered as
+//     <nav aria-label="Breadcrumb"><ol><li><a href="/">Home</a></li>...</ol><script type="application/ld+json">
+// with a schema.org BreadcrumbList for search engines.
+//
+
+// A crumb of the trail: a link, or a submenu caption without a link.
+type breadcrumb struct {
+	id   string
+	name string
+	href string
+}
+
+// schema.org ListItem of a BreadcrumbList.
+type breadcrumbListItem struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Item     string `json:"item,omitempty"`
+}
+
+// Create the breadcrumb trail for the request from a navigation tree, e.g. a Menu. The trail ends
+// with the link that matches the request path, as for Menu.MarkActive, and has a crumb for each
+// submenu containing it. If the top level of the tree has a link to "/", the trail starts with it.
+// Submenus are crumbs with their caption text and no link.
+//
+// The result is a ContentTypeBreadcrumbs element: an ordered list of the crumbs, with the last
+// marked aria-current="page", and a ContentTypeJSONLD element with the schema.org BreadcrumbList.
+// Returns nil if no link matches the request.
+func Breadcrumbs(nav HTMLElementWriter, r *http.Request) HTMLElementWriter {
+	root := toUIObject(nav)
+	best, _ := matchMenuLink(root, r)
+	if best == nil {
+		return nil
+	}
+
+	var crumbs []breadcrumb
+	for p := best.parent; p != nil && p != root; p = p.parent {
+		if p.contentType == ContentTypeMenu && len(p.text) > 0 {
+			crumbs = append([]breadcrumb{{id: p.id, name: p.text}}, crumbs...)
+		}
+	}
+	if best.parent != root || cleanMenuPath(best.attrs["href"]) != "/" {
+		for _, c := range root.ChildrenByOrder() {
+			if uio := toUIObject(c); uio.contentType == ContentTypeLink && uio.attrs["href"] == "/" {
+				crumbs = append([]breadcrumb{{id: uio.id, name: uio.text, href: "/"}}, crumbs...)
+				break
+			}
+		}
+	}
+	crumbs = append(crumbs, breadcrumb{id: best.id, name: best.text, href: best.attrs["href"]})
+
+	id := SelectorSafeId(root.id + "-breadcrumbs")
+	bc := NewElement(ContentTypeBreadcrumbs, id, "", "")
+	bc.attrs["aria-label"] = "Breadcrumb"
+	list := NewElement("ol", id+"-list", "", "")
+	for i, c := range crumbs {
+		var el *UIObject
+		if len(c.href) > 0 {
+			el = NewElement(ContentTypeLink, SelectorSafeId(c.id+"-crumb"), "", c.name)
+			el.attrs["href"] = c.href
+		} else {
+			el = NewElement("span", SelectorSafeId(c.id+"-crumb"), "", c.name)
+		}
+		if i == len(crumbs)-1 {
+			el.attrs["aria-current"] = "page"
+		}
+		list.AddChild(el)
+	}
+	bc.AddChild(list)
+	bc.AddChild(breadcrumbJSONLD(id+"-jsonld", crumbs, r))
+	return bc
+}
+
+// Create the schema.org BreadcrumbList JSON-LD for the crumbs. Links are made absolute, using the
+// request URL.
+func breadcrumbJSONLD(id string, crumbs []breadcrumb, r *http.Request) *UIObject {
+	base := &url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path}
+	if r.TLS != nil {
+		base.Scheme = "https"
+	}
+	items := make([]breadcrumbListItem, 0, len(crumbs))
+	for i, c := range crumbs {
+		item := breadcrumbListItem{Type: "ListItem", Position: i + 1, Name: c.name}
+		if u, err := base.Parse(c.href); err == nil && len(c.href) > 0 {
+			item.Item = u.String()
+		}
+		items = append(items, item)
+	}
+	b, err := json.Marshal(map[string]interface{}{
+		"@context":        "https://schema.org",
+		"@type":           "BreadcrumbList",
+		"itemListElement": items,
+	})
+	if err != nil {
+		log.Printf("goui.Breadcrumbs: %s", err)
+	}
+	return NewElement(ContentTypeJSONLD, id, "", string(b))
+}
+
+// Add the breadcrumb trail for the request, from the page navigation, to the page. Templates
+// access the trail with the {{.Breadcrumbs}} pipeline. See Breadcrumbs.
+func (uip *UIPage) AddBreadcrumbs(r *http.Request) *UIPage {
+	nav, ok := uip.PageData[PageNav].(HTMLElementWriter)
+	if !ok || nav == nil {
+		log.Printf("goui.AddBreadcrumbs: the page has no navigation, use AddNavigation.")
+		return uip
+	}
+	if bc := Breadcrumbs(nav, r); bc != nil {
+		uip.PageData[PageBreadcrumbs] = bc
+	}
+	return uip
+}
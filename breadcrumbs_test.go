@@ -0,0 +1,44 @@
+package goui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestBreadcrumbs(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		nav := newTestNav()
+		r := httptest.NewRequest(http.MethodGet, "http://example.com/admin/users/7", nil)
+		bc := Breadcrumbs(nav, r)
+		gotestutil.AssertNotNil(t, bc, "Expected a breadcrumb trail.")
+
+		x := renderString(t, bc.GetChildById("nav-breadcrumbs-list").(*UIObject))
+		gotestutil.AssertStringsEqual(t, x, `<ol id="nav-breadcrumbs-list"><li><a id="home-crumb" href="/">Home</a></li>`+
+			`<li><span id="admin-crumb">Admin</span></li>`+
+			`<li><a id="users-crumb" aria-current="page" href="/admin/users">Users</a></li></ol>`,
+			"Unexpected breadcrumb HTML. Actual: %s.", x)
+
+		var ld struct {
+			Type  string               `json:"@type"`
+			Items []breadcrumbListItem `json:"itemListElement"`
+		}
+		err := json.Unmarshal([]byte(bc.GetChildById("nav-breadcrumbs-jsonld").Text()), &ld)
+		gotestutil.AssertNil(t, err, "Unexpected JSON-LD error. %v", err)
+		gotestutil.AssertStringsEqual(t, ld.Type, "BreadcrumbList", "Unexpected JSON-LD type.")
+		gotestutil.AssertEqual(t, len(ld.Items), 3, "Expected three list items.")
+		gotestutil.AssertStringsEqual(t, ld.Items[2].Item, "http://example.com/admin/users", "Expected an absolute URL.")
+		gotestutil.AssertEqual(t, ld.Items[2].Position, 3, "Unexpected position.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		nav := newTestNav()
+		gotestutil.AssertNil(t, Breadcrumbs(nav, httptest.NewRequest(http.MethodGet, "/about", nil)),
+			"Expected no trail without a matching link.")
+
+		bc := Breadcrumbs(nav, httptest.NewRequest(http.MethodGet, "/", nil))
+		gotestutil.AssertEqual(t, bc.GetChildById("nav-breadcrumbs-list").ChildCount(), 1, "Expected only home.")
+	})
+}
@@ -0,0 +1,126 @@
+package goui
+
+import (
+	"container/list"
+)
+
+// Copies of element hierarchies, so a shared tree can be changed per request.
+//
+
+// Copy the element fields, attributes and the list of children. The children themselves are shared
+// with the original. The copy has no parent.
+func (he *UIObject) shallowCopy() *UIObject {
+	c := &UIObject{
+		text:        he.text,
+		id:          he.id,
+		class:       he.class,
+		contentType: he.contentType,
+		attrs:       make(AttributeMap, len(he.attrs)),
+		children:    make(map[string]*UIObject, len(he.children)),
+		childOrder:  list.New(),
+		rules:       append([]Rule(nil), he.rules...),
+		idGen:       he.idGen,
+		hideId:      he.hideId,
+		themeRoles:  append([]ThemeRole(nil), he.themeRoles...),
+		theme:       he.theme,
+	}
+	for k, v := range he.attrs {
+		c.attrs[k] = v
+	}
+	for l := he.childOrder.Front(); l != nil; l = l.Next() {
+		ch := l.Value.(*UIObject)
+		c.children[ch.id] = ch
+		c.childOrder.PushBack(ch)
+	}
+	return c
+}
+
+// Return a copy of the element and all descendants. The copy is the root of a new hierarchy;
+// changes to it don't affect the original.
+// Implements the HTMLElementWriter interface
+func (he *UIObject) Clone() HTMLElementWriter {
+	return he.DeepCopy()
+}
+
+// Return a copy of the element and all descendants, as with Clone().
+func (he *UIObject) DeepCopy() *UIObject {
+	c := he.shallowCopy()
+	for l := c.childOrder.Front(); l != nil; l = l.Next() {
+		ch := l.Value.(*UIObject).DeepCopy()
+		ch.parent = c
+		l.Value = ch
+		c.children[ch.id] = ch
+	}
+	return c
+}
+
+// Return a copy of the element that shares all subtrees, except for the descendants with the ids
+// and their ancestors. Those elements are copied and can be changed without affecting the original.
+// Ids that are not found are ignored. The original is only read, so copies of a tree can be made
+// concurrently, e.g. one per request, and a copy can be copied again.
+//
+// Only the fields, attributes and child list of the copied elements are private to the copy.
+// The other subtrees are shared with the original, and must not be changed. Their Parent() is in
+// the original hierarchy. A shared child can be removed from, or replaced in, a copied element;
+// the original is not changed. Moving a shared element to another element of the copy returns
+// ErrShared.
+//
+// Example: a navigation tree built at startup, changed per request:
+//
+//	nav := baseNav.CloneForWrite("home")
+//	nav.SearchChildrenById("home").AddCssClass("active")
+func (he *UIObject) CloneForWrite(ids ...string) *UIObject {
+	root := he.shallowCopy()
+	for _, id := range ids {
+		cp := root
+		// The elements from the root (exclusive) to the target, as seen from the root. The parents
+		// of shared elements are in the original, so the path is found from the root down.
+		for _, orig := range he.pathTo(id) {
+			next := cp.children[orig.id]
+			if next.parent != cp {
+				// Still shared with the original, so copy it.
+				next = orig.shallowCopy()
+				next.parent = cp
+				cp.children[orig.id] = next
+				for l := cp.childOrder.Front(); l != nil; l = l.Next() {
+					if l.Value.(*UIObject) == orig {
+						l.Value = next
+						break
+					}
+				}
+			}
+			cp = next
+		}
+	}
+	root.sharedNodes = make(map[*UIObject]bool)
+	root.findShared(root.sharedNodes)
+	return root
+}
+
+// Return the descendants from the element (exclusive) to the descendant with the id, in order, or
+// nil if there is none.
+func (he *UIObject) pathTo(id string) []*UIObject {
+	for l := he.childOrder.Front(); l != nil; l = l.Next() {
+		c := l.Value.(*UIObject)
+		if c.id == id {
+			return []*UIObject{c}
+		}
+		if p := c.pathTo(id); p != nil {
+			return append([]*UIObject{c}, p...)
+		}
+	}
+	return nil
+}
+
+// Add the subtrees of a copy that are shared with the original to the set. The copied elements are
+// the children that have the copy as their parent.
+func (he *UIObject) findShared(shared map[*UIObject]bool) {
+	for l := he.childOrder.Front(); l != nil; l = l.Next() {
+		c := l.Value.(*UIObject)
+		if c.parent == he {
+			c.findShared(shared)
+		} else {
+			shared[c] = true
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package goui
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestUIObject_DeepCopy(t *testing.T) {
+	page, _ := NewElementFromJSON(testFormJSON)
+	c := page.DeepCopy()
+
+	ok := c.SearchChildrenById("ok")
+	ok.AddCssClass("active").AddAttribute("disabled", "disabled")
+	c.SearchChildrenById("grp").RemoveChild("cancel")
+
+	orig := page.SearchChildrenById("ok")
+	gotestutil.AssertStringsEqual(t, orig.Class(), "btn btn-primary", "Expected original class unchanged. Actual: %s.",
+		orig.Class())
+	gotestutil.AssertEmptyString(t, orig.GetAttribute("disabled"), "Expected original attributes unchanged.")
+	gotestutil.AssertNotNil(t, page.SearchChildrenById("cancel"), "Expected original child \"cancel\".")
+	gotestutil.AssertEqual(t, ok.Path(), []string{"page", "f1", "grp", "ok"}, "Unexpected path in copy. Actual: %v.",
+		ok.Path())
+	gotestutil.AssertTrue(t, ok.Parent() == c.SearchChildrenById("grp"), "Expected parent in the copy.")
+	gotestutil.AssertEqual(t, queryIds(c.Query("*")), []string{"f1", "email", "grp", "ok", "save", "f2", "other"},
+		"Expected same element order in copy.")
+}
+
+func TestUIObject_CloneForWrite(t *testing.T) {
+	page, _ := NewElementFromJSON(testFormJSON)
+	c := page.CloneForWrite("ok", "dummy")
+
+	ok := c.SearchChildrenById("ok")
+	ok.AddCssClass("active")
+	gotestutil.AssertStringsEqual(t, page.SearchChildrenById("ok").Class(), "btn btn-primary",
+		"Expected original class unchanged.")
+	gotestutil.AssertEqual(t, ok.Path(), []string{"page", "f1", "grp", "ok"}, "Unexpected path in copy. Actual: %v.",
+		ok.Path())
+
+	t.Run("A1", func(t *testing.T) {
+		// Untouched subtrees are shared.
+		gotestutil.AssertTrue(t, c.SearchChildrenById("f2") == page.SearchChildrenById("f2"),
+			"Expected shared subtree \"f2\".")
+		gotestutil.AssertTrue(t, c.SearchChildrenById("cancel") == page.SearchChildrenById("cancel"),
+			"Expected shared element \"cancel\".")
+		gotestutil.AssertFalse(t, c.SearchChildrenById("grp") == page.SearchChildrenById("grp"),
+			"Expected copied ancestor \"grp\".")
+	})
+	t.Run("A2", func(t *testing.T) {
+		x := queryIds(c.Query("form.checkout submit_input"))
+		gotestutil.AssertEqual(t, x, []string{"ok", "save"}, "Unexpected query on copy. Actual: %v.", x)
+	})
+}
+
+func TestUIObject_CloneForWriteStructure(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		page, _ := NewElementFromJSON(testFormJSON)
+		c := page.CloneForWrite("ok")
+
+		gotestutil.AssertNil(t, c.RemoveChild("f2"), "Expected a shared child removed from the copy.")
+		grp := c.SearchChildrenById("grp").(*UIObject)
+		gotestutil.AssertNil(t, grp.InsertChildAt(0, NewElement("span", "note", "", "Note")), "Expected an insert in the copy.")
+		gotestutil.AssertNil(t, grp.ReplaceChild("cancel", NewElement("span", "back", "", "Back")),
+			"Expected a shared child replaced in the copy.")
+		gotestutil.AssertNil(t, c.SearchChildrenById("ok").(*UIObject).MoveTo(c), "Expected a copied element moved.")
+
+		gotestutil.AssertEqual(t, queryIds(page.Query("*")),
+			[]string{"f1", "email", "grp", "ok", "cancel", "save", "f2", "other"}, "Expected the original unchanged.")
+		gotestutil.AssertEqual(t, page.SearchChildrenById("other").Path(), []string{"page", "f2", "other"},
+			"Expected the original path unchanged.")
+		gotestutil.AssertEqual(t, page.SearchChildrenById("cancel").Path(), []string{"page", "f1", "grp", "cancel"},
+			"Expected the original path unchanged.")
+		gotestutil.AssertEqual(t, queryIds(c.Query("*")), []string{"f1", "email", "grp", "note", "back", "save", "ok"},
+			"Unexpected copy.")
+	})
+	t.Run("A2", func(t *testing.T) {
+		// A copy of a copy, and the original, can still be changed.
+		page, _ := NewElementFromJSON(testFormJSON)
+		c := page.CloneForWrite("ok")
+		c.SearchChildrenById("ok").AddCssClass("active")
+		cc := c.CloneForWrite("other")
+		cc.SearchChildrenById("other").AddCssClass("current")
+		gotestutil.AssertNil(t, cc.RemoveChild("f1"), "Expected a remove in the copy.")
+
+		gotestutil.AssertStringsEqual(t, c.SearchChildrenById("ok").Class(), "btn btn-primary active",
+			"Expected the first copy unchanged.")
+		gotestutil.AssertFalse(t, c.SearchChildrenById("other").HasClass("current"), "Expected the first copy unchanged.")
+		gotestutil.AssertNil(t, page.AppendChild(NewElement("div", "f3", "", "")), "Expected the original changeable.")
+		gotestutil.AssertNil(t, page.RemoveChild("f2"), "Expected the original changeable.")
+		gotestutil.AssertEqual(t, queryIds(c.Query("*")), []string{"f1", "email", "grp", "ok", "cancel", "save", "f2", "other"},
+			"Expected the copy unchanged.")
+	})
+	t.Run("A3", func(t *testing.T) {
+		// Copies of the same tree made concurrently, e.g. per request. Run with -race.
+		page, _ := NewElementFromJSON(testFormJSON)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				c := page.CloneForWrite("ok", "grp")
+				c.SearchChildrenById("ok").AddCssClass("active")
+				grp := c.SearchChildrenById("grp").(*UIObject)
+				grp.RemoveChild("cancel")
+				grp.AddChild(NewElement("span", fmt.Sprintf("n%d", i), "", ""))
+				c.HTML()
+			}(i)
+		}
+		wg.Wait()
+		gotestutil.AssertEqual(t, queryIds(page.Query("*")),
+			[]string{"f1", "email", "grp", "ok", "cancel", "save", "f2", "other"}, "Expected the original unchanged.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		page, _ := NewElementFromJSON(testFormJSON)
+		c := page.CloneForWrite("ok")
+		f2 := c.SearchChildrenById("f2").(*UIObject)
+
+		err := f2.MoveTo(c.SearchChildrenById("grp"))
+		gotestutil.AssertTrue(t, err != nil && err.(*Error).Err == ErrShared, "Expected ErrShared on a move.")
+		err = c.SearchChildrenById("grp").(*UIObject).AppendChild(page.SearchChildrenById("other"))
+		gotestutil.AssertTrue(t, err != nil && err.(*Error).Err == ErrShared, "Expected ErrShared on a move.")
+
+		gotestutil.AssertEqual(t, queryIds(page.Query("*")),
+			[]string{"f1", "email", "grp", "ok", "cancel", "save", "f2", "other"}, "Expected the original unchanged.")
+		gotestutil.AssertTrue(t, c.SearchChildrenById("f2") == f2, "Expected f2 in the copy.")
+	})
+}
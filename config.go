@@ -9,6 +9,7 @@ import (
 	"html/template"
 	"github.com/spf13/viper"
 	"runtime"
+	"crypto/rand"
 )
 
 const (
@@ -17,12 +18,18 @@ const (
 	CfgHomepage = "homepage"
 	CfgReload = "dynamicreload"
 	CfgPattern = "tmplpattern"
+	CfgCSRFKey = "csrfkey"
+	CfgTheme = "theme"
 )
 
 type UIContext struct {
 	sync.Mutex
 	t *template.Template
 	p *viper.Viper
+	// Generator for the ids of elements created by the context. See NewElement.
+	idGen IdGenerator
+	// Theme of elements created by the context. See SetTheme.
+	theme Theme
 }
 
 var (
@@ -76,6 +83,7 @@ func init() {
 	}
 	defaultCfg.t.Funcs(template.FuncMap{
 		"StripWhitespace": StripWhitespace,
+		"ThemeClass": ThemeClass,
 	})
 
 	log.Println("Default Context Settings:")
@@ -102,6 +110,58 @@ func (uic *UIContext) TemplatePaths() []string {
 	return uic.p.GetStringSlice(CfgTemplatePath)
 }
 
+// Set the generator used for the ids of children added without one to elements created by the
+// context. The generator only applies to this context; nil restores the default.
+func (uic *UIContext) SetIdGenerator(g IdGenerator) *UIContext {
+	uic.Lock()
+	defer uic.Unlock()
+	uic.idGen = g
+	return uic
+}
+
+// Return the generator used for element ids. The default is PathIdGenerator.
+func (uic *UIContext) IdGenerator() IdGenerator {
+	if uic.idGen != nil {
+		return uic.idGen
+	}
+	return PathIdGenerator{}
+}
+
+// Create an element, as the package NewElement, that uses the context IdGenerator for the ids of
+// its descendants, and the context theme. Without a generator or theme set on the context, the
+// element uses the ones of the element it is added to.
+func (uic *UIContext) NewElement(cType string, id string, className string, text string) *UIObject {
+	uio := NewElement(cType, id, className, text)
+	uio.idGen = uic.idGen
+	uio.theme = uic.theme
+	return uio
+}
+
+// Set the key used to sign CSRF tokens. All servers of a site need the same key, so tokens stay
+// valid across restarts and servers. The key can also be set in the config file, as "csrfkey".
+func (uic *UIContext) SetCSRFKey(key []byte) *UIContext {
+	uic.Lock()
+	defer uic.Unlock()
+	uic.p.Set(CfgCSRFKey, string(key))
+	return uic
+}
+
+// Return the key used to sign CSRF tokens. If no key is configured, a random key, created once
+// for the program, is used, and tokens are only valid until the program exits.
+func (uic *UIContext) CSRFKey() []byte {
+	if key := uic.p.GetString(CfgCSRFKey); len(key) > 0 {
+		return []byte(key)
+	}
+	csrfRandomKeyOnce.Do(func() {
+		csrfRandomKey = make([]byte, csrfNonceLen)
+		if _, err := rand.Read(csrfRandomKey); err != nil {
+			log.Panicf("goui.CSRFKey: %s", err)
+		}
+		log.Printf("goui.CSRFKey: no %q configured, using a random key.", CfgCSRFKey)
+	})
+	return csrfRandomKey
+}
+
 // Add search paths to the configuration
 func (uic *UIContext) AddTemplatePaths(paths...string) *UIContext {
 	uic.Lock()
@@ -0,0 +1,257 @@
+package goui
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Protection against cross-site request forgery. CSRFMiddleware gives each browser session a token,
+// signed with the key from UIContext.CSRFKey, in a cookie. A UIPage rendered to the response writer
+// of the middleware adds the token to its forms, and a POST, PUT, PATCH or DELETE request is only
+// accepted if it sends the token back, in the CSRFFieldName form field or the CSRFHeaderName header.
+//
+// Example:
+//     http.Handle("/signup", CSRFMiddleware(http.HandlerFunc(signup)))
+//
+//     func signup(w http.ResponseWriter, r *http.Request) {
+//         page := NewPage(GetUIConfig(), "Sign up", "signup.html")
+//         page.AddPageData(map[string]interface{}{"Form": form})
+//         page.ExecuteTemplate(w, "")
+//     }
+
+const (
+	// The form field name of the token.
+	CSRFFieldName = "csrf_token"
+	// The request header for the token, for JavaScript requests.
+	CSRFHeaderName = "X-CSRF-Token"
+	// The cookie with the session token.
+	CSRFCookieName = "goui_csrf"
+
+	csrfNonceLen = 32
+)
+
+// A request has no token, or the token is not valid for the session.
+var ErrCSRFToken = errors.New("missing or invalid CSRF token")
+
+// The key used when none is configured.
+var (
+	csrfRandomKey     []byte
+	csrfRandomKeyOnce sync.Once
+)
+
+type csrfContextKey struct{}
+
+// The response writer passed on by CSRFMiddleware, with the session token for UIPage.
+type csrfResponseWriter struct {
+	http.ResponseWriter
+	token string
+}
+
+func (w *csrfResponseWriter) csrfToken() string {
+	return w.token
+}
+
+// Return the wrapped writer, for http.ResponseController.
+func (w *csrfResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Implements the http.Flusher interface, if the wrapped writer does.
+func (w *csrfResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Implements the http.Hijacker interface, if the wrapped writer does.
+func (w *csrfResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Return the token of a writer from CSRFMiddleware, or "".
+func csrfWriterToken(w http.ResponseWriter) string {
+	for w != nil {
+		if cw, ok := w.(interface{ csrfToken() string }); ok {
+			return cw.csrfToken()
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		w = u.Unwrap()
+	}
+	return ""
+}
+
+// Create a token: a random nonce, and its signature.
+func newCSRFToken(key []byte) string {
+	nonce := make([]byte, csrfNonceLen)
+	if _, err := rand.Read(nonce); err != nil {
+		log.Panicf("goui.newCSRFToken: %s", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(nonce) + "." + signCSRFNonce(key, nonce)
+}
+
+func signCSRFNonce(key, nonce []byte) string {
+	m := hmac.New(sha256.New, key)
+	m.Write(nonce)
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+// Reports whether the token was signed with the key.
+func validCSRFToken(key []byte, token string) bool {
+	i := strings.IndexByte(token, '.')
+	if i < 0 {
+		return false
+	}
+	nonce, err := base64.RawURLEncoding.DecodeString(token[:i])
+	if err != nil || len(nonce) != csrfNonceLen {
+		return false
+	}
+	return hmac.Equal([]byte(token[i+1:]), []byte(signCSRFNonce(key, nonce)))
+}
+
+// Reports whether the method can change state on the server, and needs a token.
+func csrfUnsafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false
+	}
+	return true
+}
+
+// Check the CSRF token of each request. A session without a valid token cookie is given a new
+// token. Requests with an unsafe method are rejected with 403 Forbidden, unless they send the
+// session token in the CSRFFieldName form field or the CSRFHeaderName header.
+// Handlers read the token with CSRFToken, and a UIPage rendered to the response writer gets the
+// token without AddCSRFToken. Tokens are signed with the key of the default context; see
+// UIContext.CSRFMiddleware.
+func CSRFMiddleware(next http.Handler) http.Handler {
+	return GetUIConfig().CSRFMiddleware(next)
+}
+
+// Check the CSRF token of each request, as the package CSRFMiddleware, with tokens signed with the
+// key of the context.
+func (uic *UIContext) CSRFMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := uic.CSRFKey()
+		token := ""
+		if c, err := r.Cookie(CSRFCookieName); err == nil && validCSRFToken(key, c.Value) {
+			token = c.Value
+		}
+		if csrfUnsafeMethod(r.Method) {
+			sent := r.Header.Get(CSRFHeaderName)
+			if len(sent) == 0 {
+				sent = r.PostFormValue(CSRFFieldName)
+			}
+			if len(token) == 0 || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+				log.Printf("goui.CSRFMiddleware: %s %s, %s", r.Method, r.URL.Path, ErrCSRFToken)
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+		}
+		if len(token) == 0 {
+			token = newCSRFToken(key)
+			http.SetCookie(w, &http.Cookie{Name: CSRFCookieName, Value: token, Path: "/", HttpOnly: true,
+				Secure: r.TLS != nil, SameSite: http.SameSiteLaxMode})
+		}
+		next.ServeHTTP(&csrfResponseWriter{w, token}, r.WithContext(context.WithValue(r.Context(), csrfContextKey{}, token)))
+	})
+}
+
+// Return the CSRF token of the request, or "" if the request was not handled by CSRFMiddleware.
+func CSRFToken(r *http.Request) string {
+	token, _ := r.Context().Value(csrfContextKey{}).(string)
+	return token
+}
+
+// Add a hidden input with the CSRF token as the first child of the form. An existing token
+// input is replaced. Forms in a UIPage get the token when the page is rendered; use this for forms
+// rendered some other way.
+func (f *Form) SetCSRFToken(token string) *Form {
+	id := SelectorSafeId(f.id + "-" + CSRFFieldName)
+	input := NewHiddenInput(CSRFFieldName, token).SetId(id)
+	var err error
+	if f.GetChildById(id) != nil {
+		err = f.ReplaceChild(id, input)
+	} else {
+		err = f.InsertChildAt(0, input)
+	}
+	if err != nil {
+		log.Printf("goui.SetCSRFToken: %s", err)
+	}
+	return f
+}
+
+// Return a copy of the element with the CSRF token added to each form in it, or the element
+// itself if it has no forms. The element is not changed, so it can be shared by requests. The copy
+// has the type of the element, e.g. *Form.
+func withCSRFToken(ui HTMLElementWriter, token string) HTMLElementWriter {
+	uio := toUIObject(ui)
+	var ids []string
+	uio.walk(func(o *UIObject, depth int) WalkAction {
+		if o.contentType == ContentTypeForm {
+			ids = append(ids, o.id)
+		}
+		return WalkContinue
+	}, 0)
+	if len(ids) == 0 {
+		return ui
+	}
+	// Only the forms and their ancestors are copied, and the forms are copied with their
+	// descendants, so the token input is only added to the copy.
+	c := uio.CloneForWrite(ids...)
+	for _, id := range ids {
+		x := c.SearchChildrenById(id)
+		if x == nil {
+			continue
+		}
+		form := toUIObject(x)
+		deep := form.DeepCopy()
+		if form == c {
+			c = deep
+		} else if err := form.parent.ReplaceChild(id, deep); err != nil {
+			log.Printf("goui.withCSRFToken: %s", err)
+			continue
+		}
+		AsForm(deep).SetCSRFToken(token)
+	}
+	return rewrap(ui, c)
+}
+
+// Return a copy of the wrapper ui, e.g. a *Form, with the element uio in place of its *UIObject.
+// Returns uio if ui is not a wrapper.
+func rewrap(ui HTMLElementWriter, uio *UIObject) HTMLElementWriter {
+	v := reflect.ValueOf(ui)
+	if _, ok := ui.(*UIObject); ok || v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return uio
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+	for i := 0; i < c.Elem().NumField(); i++ {
+		f := c.Elem().Field(i)
+		if !c.Elem().Type().Field(i).Anonymous || f.Kind() != reflect.Ptr || !f.CanSet() {
+			continue
+		}
+		if inner, ok := f.Interface().(HTMLElementWriter); ok {
+			f.Set(reflect.ValueOf(rewrap(inner, uio)))
+			return c.Interface().(HTMLElementWriter)
+		}
+	}
+	return uio
+}
@@ -0,0 +1,138 @@
+package goui
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+// Serve a request through CSRFMiddleware, and return the response and the token seen by the handler.
+func serveCSRF(r *http.Request) (*httptest.ResponseRecorder, string) {
+	var token string
+	h := CSRFMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = CSRFToken(r)
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w, token
+}
+
+func TestCSRFMiddleware(t *testing.T) {
+	GetUIConfig().SetCSRFKey([]byte("0123456789abcdef0123456789abcdef"))
+
+	w, token := serveCSRF(httptest.NewRequest(http.MethodGet, "/", nil))
+	gotestutil.AssertNotEmptyString(t, token, "Expected a token for a new session.")
+	cookies := w.Result().Cookies()
+	gotestutil.AssertTrue(t, len(cookies) == 1 && cookies[0].Value == token, "Expected the token cookie. %v", cookies)
+
+	post := func(sent string, cookie bool) int {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{CSRFFieldName: {sent}}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if cookie {
+			r.AddCookie(cookies[0])
+		}
+		w, _ := serveCSRF(r)
+		return w.Code
+	}
+	t.Run("A1", func(t *testing.T) {
+		gotestutil.AssertEqual(t, post(token, true), http.StatusOK, "Expected a valid token accepted.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		gotestutil.AssertEqual(t, post("", true), http.StatusForbidden, "Expected a missing token rejected.")
+		gotestutil.AssertEqual(t, post(token, false), http.StatusForbidden, "Expected a missing cookie rejected.")
+		other := newCSRFToken([]byte("another key"))
+		gotestutil.AssertEqual(t, post(other, true), http.StatusForbidden, "Expected another token rejected.")
+		gotestutil.AssertFalse(t, validCSRFToken(GetUIConfig().CSRFKey(), other), "Expected an invalid signature.")
+	})
+}
+
+func TestPageCSRFToken(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		f := NewForm("login", "/login", "post")
+		f.AddChild(NewTextInput("user"))
+		nav := NewElement(ContentTypeMenu, "nav", "", "")
+
+		p := &UIPage{PageData: map[string]interface{}{"Form": f, PageNav: nav}}
+		data := p.pageDataWithCSRF("tok")
+		c := data["Form"].(*Form)
+		gotestutil.AssertStringsEqual(t, strings.Join(childIds(c.UIObject), ","), "login-csrf_token,user",
+			"Expected the token input first.")
+		var b bytes.Buffer
+		c.GetChildById("login-csrf_token").Render(&b)
+		gotestutil.AssertStringsEqual(t, b.String(),
+			`<input type="hidden" id="login-csrf_token" name="csrf_token" value="tok">`, "Unexpected token input.")
+		gotestutil.AssertEqual(t, f.ChildCount(), 1, "Expected the page form unchanged.")
+		gotestutil.AssertTrue(t, data[PageNav].(*UIObject) == nav, "Expected elements without forms unchanged.")
+		gotestutil.AssertStringsEqual(t, data[PageCSRF].(string), "tok", "Expected the token in the page data.")
+
+		f.SetCSRFToken("a").SetCSRFToken("b")
+		gotestutil.AssertStringsEqual(t, f.GetChildById("login-csrf_token").GetAttribute("value"), "b",
+			"Expected the token input updated.")
+		gotestutil.AssertEqual(t, f.ChildCount(), 2, "Expected one token input.")
+	})
+	t.Run("A2", func(t *testing.T) {
+		// A form with a token input keeps its token; the copy gets the session token.
+		f := NewForm("login", "/login", "post").SetCSRFToken("old")
+		c := withCSRFToken(f, "tok").(*Form)
+		gotestutil.AssertStringsEqual(t, c.GetChildById("login-csrf_token").GetAttribute("value"), "tok",
+			"Expected the session token in the copy.")
+		gotestutil.AssertStringsEqual(t, f.GetChildById("login-csrf_token").GetAttribute("value"), "old",
+			"Expected the token of the form unchanged.")
+	})
+	t.Run("A3", func(t *testing.T) {
+		// A page rendered to the writer from CSRFMiddleware gets the token without AddCSRFToken.
+		f := NewForm("login", "/login", "post")
+		p := &UIPage{t: template.Must(template.New("page").Parse(`{{.CSRF}}|{{.Form.HTML}}`)),
+			PageData: map[string]interface{}{"Form": f}}
+		var token string
+		h := CSRFMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token = CSRFToken(r)
+			if err := p.ExecuteTemplate(w, "page"); err != nil {
+				t.Errorf("ExecuteTemplate: %s", err)
+			}
+		}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		gotestutil.AssertTrue(t, strings.HasPrefix(w.Body.String(), token+"|"), "Expected the page token. %s", w.Body)
+		gotestutil.AssertTrue(t, strings.Contains(w.Body.String(), `value="`+token+`"`),
+			"Expected the form token. %s", w.Body)
+		gotestutil.AssertEqual(t, f.ChildCount(), 0, "Expected the page form unchanged.")
+	})
+	t.Run("A4", func(t *testing.T) {
+		// A page shared by requests, rendered concurrently. Run with -race.
+		nav := NewElement("div", "main", "", "")
+		f := NewForm("login", "/login", "post")
+		f.AddChild(NewTextInput("user"))
+		nav.AddChild(f)
+		p := &UIPage{t: template.Must(template.New("page").Parse(`{{.Main.HTML}}`)),
+			PageData: map[string]interface{}{"Main": nav}}
+		h := NewUIContext().CSRFMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := p.ExecuteTemplate(w, "page"); err != nil {
+				t.Errorf("ExecuteTemplate: %s", err)
+			}
+		}))
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+				if !strings.Contains(w.Body.String(), `name="csrf_token"`) {
+					t.Errorf("Expected the token input. %s", w.Body)
+				}
+			}()
+		}
+		wg.Wait()
+		gotestutil.AssertNil(t, f.SetFieldError("user", "Unknown user"), "Expected the page form changeable.")
+		f.ClearErrors()
+		gotestutil.AssertEqual(t, f.ChildCount(), 1, "Expected the page form unchanged.")
+	})
+}
@@ -0,0 +1,154 @@
+package goui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+)
+
+// Helpers for data-* attributes, used for JavaScript configuration, and aria-* attributes and roles,
+// used for accessibility. Both are stored as ordinary attributes, so templates can still use .Attributes.
+//
+
+const (
+	dataPrefix = "data-"
+	ariaPrefix = "aria-"
+)
+
+// WAI-ARIA 1.2 states and properties, without the aria- prefix.
+var ariaAttributes = map[string]bool{
+	"activedescendant": true, "atomic": true, "autocomplete": true, "braillelabel": true,
+	"brailleroledescription": true, "busy": true, "checked": true, "colcount": true, "colindex": true,
+	"colindextext": true, "colspan": true, "controls": true, "current": true, "describedby": true,
+	"description": true, "details": true, "disabled": true, "dropeffect": true, "errormessage": true,
+	"expanded": true, "flowto": true, "grabbed": true, "haspopup": true, "hidden": true, "invalid": true,
+	"keyshortcuts": true, "label": true, "labelledby": true, "level": true, "live": true, "modal": true,
+	"multiline": true, "multiselectable": true, "orientation": true, "owns": true, "placeholder": true,
+	"posinset": true, "pressed": true, "readonly": true, "relevant": true, "required": true,
+	"roledescription": true, "rowcount": true, "rowindex": true, "rowindextext": true, "rowspan": true,
+	"selected": true, "setsize": true, "sort": true, "valuemax": true, "valuemin": true, "valuenow": true,
+	"valuetext": true,
+}
+
+// WAI-ARIA 1.2 roles, excluding abstract roles.
+var ariaRoles = map[string]bool{
+	"alert": true, "alertdialog": true, "application": true, "article": true, "banner": true,
+	"blockquote": true, "button": true, "caption": true, "cell": true, "checkbox": true, "code": true,
+	"columnheader": true, "combobox": true, "complementary": true, "contentinfo": true, "definition": true,
+	"deletion": true, "dialog": true, "document": true, "emphasis": true, "feed": true, "figure": true,
+	"form": true, "generic": true, "grid": true, "gridcell": true, "group": true, "heading": true,
+	"img": true, "insertion": true, "link": true, "list": true, "listbox": true, "listitem": true,
+	"log": true, "main": true, "marquee": true, "math": true, "menu": true, "menubar": true,
+	"menuitem": true, "menuitemcheckbox": true, "menuitemradio": true, "meter": true, "navigation": true,
+	"none": true, "note": true, "option": true, "paragraph": true, "presentation": true,
+	"progressbar": true, "radio": true, "radiogroup": true, "region": true, "row": true, "rowgroup": true,
+	"rowheader": true, "scrollbar": true, "search": true, "searchbox": true, "separator": true,
+	"slider": true, "spinbutton": true, "status": true, "strong": true, "subscript": true,
+	"superscript": true, "switch": true, "tab": true, "table": true, "tablist": true, "tabpanel": true,
+	"term": true, "textbox": true, "time": true, "timer": true, "toolbar": true, "tooltip": true,
+	"tree": true, "treegrid": true, "treeitem": true,
+}
+
+// Convert a data key to the attribute name. Keys can be camel case, as in the JavaScript dataset,
+// or hyphenated: "userId", "user-id" and "data-user-id" are all "data-user-id".
+func dataAttributeName(key string) string {
+	key = strings.TrimPrefix(key, dataPrefix)
+	var b strings.Builder
+	b.WriteString(dataPrefix)
+	for _, r := range key {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('-')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Set a data-* attribute. String values are stored as is; other values are JSON encoded, so
+// JavaScript can read them with JSON.parse(el.dataset.key).
+// A key that is not a valid attribute name, or a value that can't be encoded, is logged and not set.
+// Implements the Attribute interface
+func (he *UIObject) SetData(key string, v interface{}) HTMLElementWriter {
+	name := dataAttributeName(key)
+	if len(name) == len(dataPrefix) || !ValidAttributeName(name) {
+		log.Printf("goui.SetData: invalid data key %q", key)
+		return he
+	}
+	if s, ok := v.(string); ok {
+		return he.AddAttribute(name, s)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("goui.SetData: key %q, %s", key, err)
+		return he
+	}
+	return he.AddAttribute(name, string(b))
+}
+
+// Return the value of a data-* attribute, or "" if it isn't set.
+// Implements the Attribute interface
+func (he *UIObject) Data(key string) string {
+	return he.GetAttribute(dataAttributeName(key))
+}
+
+// Decode a JSON encoded data-* attribute into v.
+func (he *UIObject) DataValue(key string, v interface{}) error {
+	name := dataAttributeName(key)
+	s, ok := he.attrs[name]
+	if !ok {
+		return errorf(fmt.Sprintf("Element %q has no attribute %q", he.id, name), ErrNotFound)
+	}
+	return json.Unmarshal([]byte(s), v)
+}
+
+// Return all data-* attributes, keyed by the name without the "data-" prefix, e.g. "user-id".
+// Implements the Attribute interface
+func (he *UIObject) Dataset() map[string]string {
+	x := make(map[string]string, 1)
+	for k, v := range he.attrs {
+		if strings.HasPrefix(k, dataPrefix) {
+			x[strings.TrimPrefix(k, dataPrefix)] = v
+		}
+	}
+	return x
+}
+
+// Set an aria-* attribute, e.g. SetAria("expanded", false) or SetAria("aria-label", "Close").
+// The name must be a WAI-ARIA state or property. Booleans are "true" or "false", and other values
+// are formatted with fmt.Sprint. An unknown name is logged and not set.
+// Implements the Attribute interface
+func (he *UIObject) SetAria(name string, v interface{}) HTMLElementWriter {
+	prop := strings.TrimPrefix(strings.ToLower(name), ariaPrefix)
+	if !ariaAttributes[prop] {
+		log.Printf("goui.SetAria: unknown WAI-ARIA attribute %q", name)
+		return he
+	}
+	return he.AddAttribute(ariaPrefix+prop, fmt.Sprint(v))
+}
+
+// Return the value of an aria-* attribute, or "" if it isn't set.
+// Implements the Attribute interface
+func (he *UIObject) Aria(name string) string {
+	return he.GetAttribute(ariaPrefix + strings.TrimPrefix(strings.ToLower(name), ariaPrefix))
+}
+
+// Set the WAI-ARIA role attribute. Several roles, in order of preference, are separated by spaces.
+// An unknown or abstract role is logged and not set.
+// Implements the Attribute interface
+func (he *UIObject) SetRole(role string) HTMLElementWriter {
+	roles := strings.Fields(strings.ToLower(role))
+	if len(roles) == 0 {
+		log.Printf("goui.SetRole: empty role")
+		return he
+	}
+	for _, r := range roles {
+		if !ariaRoles[r] {
+			log.Printf("goui.SetRole: unknown WAI-ARIA role %q", r)
+			return he
+		}
+	}
+	return he.AddAttribute("role", strings.Join(roles, " "))
+}
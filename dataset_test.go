@@ -0,0 +1,46 @@
+package goui
+
+import (
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestUIObject_SetData(t *testing.T) {
+	uio := NewElement("div", "chart1", "", "")
+	uio.SetData("toggle", "tab").SetData("userId", 42).SetData("options", map[string]interface{}{"stacked": true})
+
+	gotestutil.AssertStringsEqual(t, uio.GetAttribute("data-toggle"), "tab", "Expected data-toggle \"tab\".")
+	gotestutil.AssertStringsEqual(t, uio.GetAttribute("data-user-id"), "42", "Expected data-user-id \"42\".")
+	gotestutil.AssertStringsEqual(t, uio.Data("user-id"), "42", "Expected data \"user-id\" of \"42\".")
+	gotestutil.AssertStringsEqual(t, uio.Data("options"), `{"stacked":true}`, "Unexpected data \"options\". Actual: %s.",
+		uio.Data("options"))
+
+	var opts struct{ Stacked bool }
+	gotestutil.AssertNil(t, uio.DataValue("options", &opts), "Expected nil error on DataValue.")
+	gotestutil.AssertTrue(t, opts.Stacked, "Expected decoded option Stacked.")
+	gotestutil.AssertNotNil(t, uio.DataValue("dummy", &opts), "Expected error for data \"dummy\".")
+
+	uio.AddAttribute("href", "/")
+	gotestutil.AssertEqual(t, uio.Dataset(), map[string]string{"toggle": "tab", "user-id": "42",
+		"options": `{"stacked":true}`}, "Unexpected dataset. Actual: %v.", uio.Dataset())
+
+	uio.SetData("bad key", 1).SetData("", 1)
+	gotestutil.AssertEqual(t, len(uio.Dataset()), 3, "Expected invalid keys not set.")
+}
+
+func TestUIObject_SetAria(t *testing.T) {
+	uio := NewElement(ContentInputButton, "b1", "", "")
+	uio.SetAria("expanded", false).SetAria("aria-Label", "Close").SetAria("bogus", "x")
+	gotestutil.AssertStringsEqual(t, uio.GetAttribute("aria-expanded"), "false", "Expected aria-expanded \"false\".")
+	gotestutil.AssertStringsEqual(t, uio.Aria("label"), "Close", "Expected aria-label \"Close\".")
+	gotestutil.AssertEmptyString(t, uio.GetAttribute("aria-bogus"), "Expected unknown aria attribute not set.")
+}
+
+func TestUIObject_SetRole(t *testing.T) {
+	uio := NewElement("div", "d1", "", "")
+	uio.SetRole("navigation")
+	gotestutil.AssertStringsEqual(t, uio.GetAttribute("role"), "navigation", "Expected role \"navigation\".")
+	uio.SetRole("widget")
+	gotestutil.AssertStringsEqual(t, uio.GetAttribute("role"), "navigation", "Expected abstract role not set.")
+}
@@ -0,0 +1,402 @@
+package goui
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTML data tables built from a slice, sorted and paginated on the server from the query
+// parameters of the request, e.g. ?sort=name&dir=desc&page=2. This is separate from the
+// GoogleChart "Table" type, which is drawn in the browser.
+//
+// Example:
+//     users := NewDataTable("users", list,
+//         TableColumn{Key: "name", Header: "Name"},
+//         TableColumn{Key: "joined", Header: "Joined", Format: func(v interface{}) string {
+//             return v.(time.Time).Format("2006-01-02")
+//         }},
+//     ).PageSize(20)
+//     page.AddPageData(map[string]interface{}{"Users": users.ForRequest(r)})
+
+const (
+	// Query parameter with the key of the sort column.
+	TableSortParam = "sort"
+	// Query parameter with the sort direction, "asc" or "desc".
+	TableDirParam = "dir"
+	// Query parameter with the page number, from 1.
+	TablePageParam = "page"
+
+	// Number of page links shown on each side of the current page.
+	tablePageWindow = 2
+)
+
+// A column of a data table.
+type TableColumn struct {
+	// The key of the column in the sort parameter. Columns without a key are not sortable.
+	Key string
+	// The header text.
+	Header string
+	// Returns the value of the column for a row. If nil, the value is the struct field named Key,
+	// ignoring case, or the map entry with the key Key, converted to the map key type if it is a
+	// number.
+	Value func(row interface{}) interface{}
+	// Formats a value for the cell. If nil, the value is formatted with fmt.Sprint.
+	Format func(v interface{}) string
+	// CSS class of the header and the cells of the column.
+	Class string
+}
+
+// The value of the column for a row.
+func (tc TableColumn) value(row interface{}) interface{} {
+	if tc.Value != nil {
+		return tc.Value(row)
+	}
+	rv := reflect.Indirect(reflect.ValueOf(row))
+	switch rv.Kind() {
+	case reflect.Struct:
+		f := rv.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, tc.Key) })
+		if f.IsValid() && f.CanInterface() {
+			return f.Interface()
+		}
+	case reflect.Map:
+		if k, ok := mapKey(rv.Type().Key(), tc.Key); ok {
+			if v := rv.MapIndex(k); v.IsValid() {
+				return v.Interface()
+			}
+		}
+	}
+	return nil
+}
+
+// Convert a column key to a key of the map key type, e.g. "2" for a map[int]string. Returns false if
+// the key can't be converted, and the column has no value.
+func mapKey(kt reflect.Type, key string) (reflect.Value, bool) {
+	k := reflect.ValueOf(key)
+	switch kt.Kind() {
+	case reflect.String:
+		return k.Convert(kt), true
+	case reflect.Interface:
+		return k, k.Type().Implements(kt)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, kt.Bits())
+		if err != nil {
+			return k, false
+		}
+		return reflect.ValueOf(n).Convert(kt), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(key, 10, kt.Bits())
+		if err != nil {
+			return k, false
+		}
+		return reflect.ValueOf(n).Convert(kt), true
+	}
+	return k, false
+}
+
+func (tc TableColumn) format(v interface{}) string {
+	if tc.Format != nil {
+		return tc.Format(v)
+	}
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// A table of rows from a slice. The table is built as an element tree for each request by ForRequest.
+type DataTable struct {
+	id        string
+	columns   []TableColumn
+	rows      []interface{}
+	pageSize  int
+	sortKey   string
+	desc      bool
+	emptyText string
+	theme     Theme
+}
+
+// Create a data table with the rows of a slice. A value that is not a slice is logged, and the
+// table has no rows.
+func NewDataTable(id string, rows interface{}, columns ...TableColumn) *DataTable {
+	return GetUIConfig().NewDataTable(id, rows, columns...)
+}
+
+// Create a data table, as NewDataTable, with the context theme.
+func (uic *UIContext) NewDataTable(id string, rows interface{}, columns ...TableColumn) *DataTable {
+	dt := &DataTable{id: id, columns: columns, emptyText: "No data.", theme: uic.theme}
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		log.Printf("goui.NewDataTable: %T is not a slice", rows)
+		return dt
+	}
+	dt.rows = make([]interface{}, rv.Len())
+	for i := range dt.rows {
+		dt.rows[i] = rv.Index(i).Interface()
+	}
+	return dt
+}
+
+// Set the number of rows on a page. 0, the default, shows all rows on one page.
+func (dt *DataTable) PageSize(n int) *DataTable {
+	dt.pageSize = n
+	return dt
+}
+
+// Set the sort column, by key, used when the request has no sort parameter.
+func (dt *DataTable) DefaultSort(key string, desc bool) *DataTable {
+	dt.sortKey, dt.desc = key, desc
+	return dt
+}
+
+// Set the text shown when there are no rows.
+func (dt *DataTable) EmptyText(s string) *DataTable {
+	dt.emptyText = s
+	return dt
+}
+
+func (dt *DataTable) column(key string) (TableColumn, bool) {
+	for _, c := range dt.columns {
+		if len(c.Key) > 0 && c.Key == key {
+			return c, true
+		}
+	}
+	return TableColumn{}, false
+}
+
+// Build the table for the request: the rows sorted by the sort and dir parameters, and the page
+// from the page parameter. An unknown sort column or page is ignored.
+//
+// The result is a <div> with a <table>, with links in the headers of sortable columns, and a <nav>
+// of page links if there is more than one page. The table has the class "data-table", the page
+// links "page-link", and the current page link "active" and aria-current="page", with the classes
+// of the theme roles ThemeTable, ThemePagination, ThemePageLink and ThemePageActive.
+func (dt *DataTable) ForRequest(r *http.Request) *UIObject {
+	q := r.URL.Query()
+	sortKey, desc := dt.sortKey, dt.desc
+	if k := q.Get(TableSortParam); len(k) > 0 {
+		if _, ok := dt.column(k); ok {
+			sortKey, desc = k, strings.EqualFold(q.Get(TableDirParam), "desc")
+		}
+	}
+
+	rows := append([]interface{}(nil), dt.rows...)
+	if col, ok := dt.column(sortKey); ok {
+		sort.SliceStable(rows, func(i, j int) bool {
+			c := compareValues(col.value(rows[i]), col.value(rows[j]))
+			if desc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+
+	pages, page := 1, 1
+	if dt.pageSize > 0 && len(rows) > dt.pageSize {
+		pages = (len(rows) + dt.pageSize - 1) / dt.pageSize
+		if n, err := strconv.Atoi(q.Get(TablePageParam)); err == nil && n >= 1 && n <= pages {
+			page = n
+		}
+		end := page * dt.pageSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		rows = rows[(page-1)*dt.pageSize : end]
+	}
+
+	root := NewElement("div", dt.id, "data-table-container", "")
+	root.theme = dt.theme
+	table := NewElement("table", SelectorSafeId(dt.id+"-table"), "data-table", "")
+	root.AddChild(table)
+	table.AddThemeClass(ThemeTable)
+	dt.addHead(table, q, sortKey, desc)
+	dt.addBody(table, rows)
+	if pages > 1 {
+		root.AddChild(dt.pagination(q, page, pages))
+	}
+	return root
+}
+
+// Add the <thead>. Sortable headers link to the table sorted by the column, ascending, or
+// descending if it is already sorted ascending.
+func (dt *DataTable) addHead(table *UIObject, q url.Values, sortKey string, desc bool) {
+	thead := NewElement("thead", SelectorSafeId(dt.id+"-head"), "", "")
+	table.AddChild(thead)
+	tr := newTableElement("tr", "", "")
+	thead.AddChild(tr)
+	for _, c := range dt.columns {
+		th := newTableElement("th", c.Class, "")
+		th.attrs["scope"] = "col"
+		tr.AddChild(th)
+		if len(c.Key) == 0 {
+			th.SetText(c.Header)
+			continue
+		}
+		dir := "asc"
+		if c.Key == sortKey {
+			if desc {
+				th.attrs["aria-sort"] = "descending"
+			} else {
+				th.attrs["aria-sort"] = "ascending"
+				dir = "desc"
+			}
+		}
+		lq := copyValues(q)
+		lq.Set(TableSortParam, c.Key)
+		lq.Set(TableDirParam, dir)
+		lq.Del(TablePageParam)
+		a := newTableElement(ContentTypeLink, "", c.Header)
+		a.attrs["href"] = "?" + lq.Encode()
+		th.AddChild(a)
+	}
+}
+
+func (dt *DataTable) addBody(table *UIObject, rows []interface{}) {
+	tbody := NewElement("tbody", SelectorSafeId(dt.id+"-body"), "", "")
+	table.AddChild(tbody)
+	if len(rows) == 0 {
+		tr := newTableElement("tr", "", "")
+		tbody.AddChild(tr)
+		td := newTableElement("td", "", dt.emptyText)
+		td.attrs["colspan"] = strconv.Itoa(len(dt.columns))
+		tr.AddChild(td)
+		return
+	}
+	for _, row := range rows {
+		tr := newTableElement("tr", "", "")
+		tbody.AddChild(tr)
+		for _, c := range dt.columns {
+			tr.AddChild(newTableElement("td", c.Class, c.format(c.value(row))))
+		}
+	}
+}
+
+// Create the <nav> of page links: previous, the first and last pages, the pages near the current
+// page, and next. Gaps in the page numbers are shown with an ellipsis.
+func (dt *DataTable) pagination(q url.Values, page, pages int) *UIObject {
+	nav := NewElement("nav", SelectorSafeId(dt.id+"-pages"), "", "")
+	nav.theme = dt.theme
+	nav.attrs["aria-label"] = "Pagination"
+	list := NewElement(ContentTypeMenu, SelectorSafeId(dt.id+"-pagelist"), "pagination", "")
+	nav.AddChild(list)
+	list.AddThemeClass(ThemePagination)
+
+	link := func(text string, n int, label string) {
+		a := newTableElement(ContentTypeLink, "page-link", text)
+		list.AddChild(a)
+		a.AddThemeClass(ThemePageLink)
+		lq := copyValues(q)
+		lq.Set(TablePageParam, strconv.Itoa(n))
+		a.attrs["href"] = "?" + lq.Encode()
+		if len(label) > 0 {
+			a.attrs["aria-label"] = label
+		}
+		if n == page && len(label) == 0 {
+			a.AddCssClass("active")
+			a.AddThemeClass(ThemePageActive)
+			a.attrs["aria-current"] = "page"
+		}
+	}
+	if page > 1 {
+		link("«", page-1, "Previous")
+	}
+	last := 0
+	for n := 1; n <= pages; n++ {
+		if n != 1 && n != pages && (n < page-tablePageWindow || n > page+tablePageWindow) {
+			continue
+		}
+		if n > last+1 {
+			list.AddChild(newTableElement("span", "page-gap", "…"))
+		}
+		link(strconv.Itoa(n), n, "")
+		last = n
+	}
+	if page < pages {
+		link("»", page+1, "Next")
+	}
+	return nav
+}
+
+// Create an element of the table without an id. It is given an id when added, for the tree, but
+// the id is not rendered, so large tables don't have an id for every cell.
+func newTableElement(cType, className, text string) *UIObject {
+	uio := NewElement(cType, "", className, text)
+	uio.hideId = true
+	return uio
+}
+
+func copyValues(q url.Values) url.Values {
+	x := make(url.Values, len(q))
+	for k, v := range q {
+		x[k] = append([]string(nil), v...)
+	}
+	return x
+}
+
+// Compare two column values: numbers, strings, booleans and times by value, and other values by
+// their fmt.Sprint text. nil is less than any other value. Returns -1, 0 or 1.
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			switch {
+			case ta.Before(tb):
+				return -1
+			case ta.After(tb):
+				return 1
+			}
+			return 0
+		}
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if fa, ok := numberValue(va); ok {
+		if fb, ok := numberValue(vb); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	sa, sb := fmt.Sprint(a), fmt.Sprint(b)
+	if va.Kind() == reflect.String && vb.Kind() == reflect.String {
+		// Case-insensitive, so "apple" sorts with "Apple".
+		if c := strings.Compare(strings.ToLower(sa), strings.ToLower(sb)); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(sa, sb)
+}
+
+// Return a number, or a bool as 0 or 1, as a float64.
+func numberValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
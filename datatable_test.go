@@ -0,0 +1,141 @@
+package goui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+type testUser struct {
+	Name string
+	Age  int
+}
+
+func newTestTable() *DataTable {
+	users := []testUser{{"carol", 41}, {"Bob", 25}, {"alice", 33}, {"dave", 19}, {"Eve", 52}}
+	return NewDataTable("users", users,
+		TableColumn{Key: "name", Header: "Name", Format: func(v interface{}) string {
+			s := v.(string)
+			return strings.ToUpper(s[:1]) + s[1:]
+		}},
+		TableColumn{Key: "age", Header: "Age", Class: "num"},
+		TableColumn{Header: "Adult", Value: func(row interface{}) interface{} { return row.(testUser).Age >= 21 }},
+	)
+}
+
+// Return the text of the first cell of each body row.
+func firstColumn(table *UIObject) []string {
+	var x []string
+	for _, tr := range table.SearchChildrenById("users-body").ChildrenByOrder() {
+		x = append(x, tr.ChildrenByOrder()[0].Text())
+	}
+	return x
+}
+
+func TestDataTableSort(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		table := newTestTable().ForRequest(httptest.NewRequest(http.MethodGet, "/users?sort=name&dir=desc", nil))
+		gotestutil.AssertStringsEqual(t, strings.Join(firstColumn(table), ","), "Eve,Dave,Carol,Bob,Alice",
+			"Expected rows sorted by name, descending.")
+		th := table.QueryOne("thead th")
+		gotestutil.AssertStringsEqual(t, th.GetAttribute("aria-sort"), "descending", "Expected aria-sort.")
+		gotestutil.AssertStringsEqual(t, th.ChildrenByOrder()[0].GetAttribute("href"), "?dir=asc&sort=name",
+			"Expected the header to link to the other direction.")
+
+		table = newTestTable().ForRequest(httptest.NewRequest(http.MethodGet, "/users?sort=age", nil))
+		gotestutil.AssertStringsEqual(t, strings.Join(firstColumn(table), ","), "Dave,Bob,Alice,Carol,Eve",
+			"Expected rows sorted by age.")
+		td := table.QueryOne("tbody td.num")
+		gotestutil.AssertStringsEqual(t, td.Text(), "19", "Expected the column class on cells.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		table := newTestTable().ForRequest(httptest.NewRequest(http.MethodGet, "/users?sort=password", nil))
+		gotestutil.AssertStringsEqual(t, strings.Join(firstColumn(table), ","), "Carol,Bob,Alice,Dave,Eve",
+			"Expected an unknown sort column ignored.")
+		x := renderString(t, table.QueryOne("thead th:nth-child(3)").(*UIObject))
+		gotestutil.AssertStringsEqual(t, x, `<th scope="col">Adult</th>`,
+			"Expected no link for a column without a key. Actual: %s.", x)
+
+		empty := NewDataTable("users", []testUser{}, TableColumn{Key: "name", Header: "Name"})
+		x = renderString(t, empty.ForRequest(httptest.NewRequest(http.MethodGet, "/", nil)).QueryOne("tbody td").(*UIObject))
+		gotestutil.AssertStringsEqual(t, x, `<td colspan="1">No data.</td>`,
+			"Unexpected empty table. Actual: %s.", x)
+	})
+}
+
+func TestDataTableMapRows(t *testing.T) {
+	type code int
+	t.Run("A1", func(t *testing.T) {
+		rows := []map[string]interface{}{{"name": "bob", "age": 25}, {"name": "alice", "age": 33}}
+		table := NewDataTable("users", rows, TableColumn{Key: "name", Header: "Name"}).
+			ForRequest(httptest.NewRequest(http.MethodGet, "/users?sort=name", nil))
+		gotestutil.AssertStringsEqual(t, strings.Join(firstColumn(table), ","), "alice,bob",
+			"Expected map rows sorted by name.")
+		x := renderString(t, table.SearchChildrenById("users-body").(*UIObject))
+		gotestutil.AssertStringsEqual(t, x, `<tbody id="users-body"><tr><td>alice</td></tr><tr><td>bob</td></tr></tbody>`,
+			"Expected no ids on rows and cells. Actual: %s.", x)
+		b, err := json.Marshal(table)
+		gotestutil.AssertNil(t, err, "Unexpected error on MarshalJSON. %v", err)
+		rt, err := NewElementFromJSON(string(b))
+		gotestutil.AssertNil(t, err, "Unexpected error on NewElementFromJSON. %v", err)
+		gotestutil.AssertStringsEqual(t, renderString(t, rt), renderString(t, table),
+			"Expected the same table after a JSON round trip.")
+
+		codes := []map[code]string{{1: "one", 2: "two"}}
+		table = NewDataTable("users", codes, TableColumn{Key: "2", Header: "Two"}).
+			ForRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		gotestutil.AssertStringsEqual(t, strings.Join(firstColumn(table), ","), "two",
+			"Expected the key converted to the map key type.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		codes := []map[code]string{{1: "one"}}
+		table := NewDataTable("users", codes, TableColumn{Key: "name", Header: "Name"}).
+			ForRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		gotestutil.AssertStringsEqual(t, strings.Join(firstColumn(table), ","), "",
+			"Expected an empty cell for a key that isn't a map key.")
+		floats := []map[float64]string{{1: "one"}}
+		table = NewDataTable("users", floats, TableColumn{Key: "1", Header: "One"}).
+			ForRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		gotestutil.AssertStringsEqual(t, strings.Join(firstColumn(table), ","), "",
+			"Expected an empty cell for an unsupported key type.")
+	})
+}
+
+func TestDataTablePagination(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		table := newTestTable().PageSize(2).ForRequest(
+			httptest.NewRequest(http.MethodGet, "/users?sort=age&page=2", nil))
+		gotestutil.AssertStringsEqual(t, strings.Join(firstColumn(table), ","), "Alice,Carol", "Expected page 2.")
+		var x []string
+		for _, a := range table.Query("#users-pagelist .page-link") {
+			x = append(x, a.Text()+"="+a.GetAttribute("href"))
+		}
+		gotestutil.AssertStringsEqual(t, strings.Join(x, " "),
+			"«=?page=1&sort=age 1=?page=1&sort=age 2=?page=2&sort=age 3=?page=3&sort=age »=?page=3&sort=age",
+			"Unexpected page links.")
+		current := table.QueryOne(".page-link.active")
+		gotestutil.AssertStringsEqual(t, current.Text(), "2", "Expected page 2 current.")
+		gotestutil.AssertStringsEqual(t, current.Aria("current"), "page", "Expected aria-current.")
+	})
+	t.Run("A2", func(t *testing.T) {
+		uic := NewUIContext().SetTheme("bulma")
+		table := uic.NewDataTable("users", []testUser{{"carol", 41}, {"Bob", 25}},
+			TableColumn{Key: "name", Header: "Name"}).PageSize(1).
+			ForRequest(httptest.NewRequest(http.MethodGet, "/users", nil))
+		gotestutil.AssertStringsEqual(t, table.QueryOne("table").Class(), "data-table table is-striped",
+			"Expected the bulma table classes.")
+		gotestutil.AssertStringsEqual(t, table.QueryOne(".page-link.active").Class(),
+			"page-link pagination-link active is-current", "Expected the bulma page link classes.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		table := newTestTable().PageSize(1).ForRequest(httptest.NewRequest(http.MethodGet, "/users?page=9", nil))
+		gotestutil.AssertStringsEqual(t, strings.Join(firstColumn(table), ","), "Carol", "Expected page 1 for an invalid page.")
+		gotestutil.AssertEqual(t, len(table.Query(".page-gap")), 1, "Expected a gap before the last page.")
+		gotestutil.AssertNil(t, newTestTable().ForRequest(httptest.NewRequest(http.MethodGet, "/", nil)).
+			SearchChildrenById("users-pages"), "Expected no page links for a single page.")
+	})
+}
@@ -0,0 +1,260 @@
+package goui
+
+import (
+	"fmt"
+	"log"
+	"sort"
+)
+
+// Structural differences between two element trees, and applying them to a tree.
+// Elements are matched by id, so ids are expected to be unique in each tree.
+//
+
+type PatchOp string
+
+const (
+	// Insert Element as a child of ParentId at Index.
+	PatchAdd PatchOp = "add"
+	// Remove the element Id from its parent.
+	PatchRemove PatchOp = "remove"
+	// Move the element Id to be a child of ParentId at Index.
+	PatchMove PatchOp = "move"
+	// Set the attribute Name of element Id to Value. The name "id" changes the element id.
+	PatchAttribute PatchOp = "attribute"
+	// Remove the attribute Name from element Id.
+	PatchRemoveAttribute PatchOp = "remove_attribute"
+	// Set the CSS classes of element Id to Value.
+	PatchClass PatchOp = "class"
+	// Set the text of element Id to Value.
+	PatchText PatchOp = "text"
+	// Set the content type of element Id to Value.
+	PatchContentType PatchOp = "type"
+)
+
+// A single change to an element tree. The fields used depend on the Op.
+// The JSON encoding is suitable for sending to a browser.
+type Patch struct {
+	Op       PatchOp   `json:"op"`
+	Id       string    `json:"id,omitempty"`
+	ParentId string    `json:"parent,omitempty"`
+	Index    int       `json:"index"`
+	Name     string    `json:"name,omitempty"`
+	Value    string    `json:"value,omitempty"`
+	Element  *UIObject `json:"element,omitempty"`
+}
+
+// Return the patches that change tree a into tree b. Applying the patches to a, in order, results
+// in a tree equivalent to b. Neither tree is changed.
+// The roots of a and b are always matched to each other. Other elements are matched by id, and
+// patches report added, removed and moved children, and attribute, class, text and content type changes.
+func Diff(a, b *UIObject) []Patch {
+	d := &differ{index: make(map[string]*UIObject, 1), bIds: make(map[string]bool, 1)}
+	d.scratch = a.DeepCopy()
+	d.scratch.id = b.id
+	d.indexTree(d.scratch)
+	b.walk(func(uio *UIObject, depth int) WalkAction {
+		d.bIds[uio.id] = true
+		return WalkContinue
+	}, 0)
+
+	d.diffFields(d.scratch, b)
+	d.diffChildren(d.scratch, b)
+
+	// Whatever is left from a, and isn't in b, is removed. Only the top-most element is reported.
+	var removed []*UIObject
+	d.scratch.walk(func(uio *UIObject, depth int) WalkAction {
+		if !d.bIds[uio.id] {
+			removed = append(removed, uio)
+			return WalkSkipChildren
+		}
+		return WalkContinue
+	}, 0)
+	for _, uio := range removed {
+		d.emit(Patch{Op: PatchRemove, Id: uio.id, ParentId: uio.parent.id})
+	}
+	if a.id != b.id {
+		// The scratch root took the id of b, so patches address it by b.id. Report the change first.
+		d.patches = append([]Patch{{Op: PatchAttribute, Id: a.id, Name: "id", Value: b.id}}, d.patches...)
+	}
+	return d.patches
+}
+
+type differ struct {
+	// A copy of tree a, with each patch applied as it is found.
+	scratch *UIObject
+	// Elements in scratch by id.
+	index map[string]*UIObject
+	// Ids of the elements in tree b
+	bIds    map[string]bool
+	patches []Patch
+}
+
+func (d *differ) emit(p Patch) {
+	d.patches = append(d.patches, p)
+	if err := d.scratch.Apply([]Patch{p}); err != nil {
+		log.Printf("goui.Diff: %s", err)
+	}
+}
+
+func (d *differ) indexTree(uio *UIObject) {
+	uio.walk(func(x *UIObject, depth int) WalkAction {
+		d.index[x.id] = x
+		return WalkContinue
+	}, 0)
+}
+
+// Place the children of b, in order, under the matching scratch element, then recurse.
+func (d *differ) diffChildren(sp *UIObject, b *UIObject) {
+	i := 0
+	for l := b.childOrder.Front(); l != nil; l, i = l.Next(), i+1 {
+		bc := l.Value.(*UIObject)
+		sc := d.index[bc.id]
+		if sc == nil {
+			if !d.hasIndexedDescendant(bc) {
+				// An entirely new subtree.
+				d.emit(Patch{Op: PatchAdd, ParentId: sp.id, Index: i, Element: bc.DeepCopy()})
+				d.indexTree(sp.children[bc.id])
+				continue
+			}
+			// The new element contains existing elements, which are moved into it.
+			x := bc.shallowCopy()
+			x.children = make(map[string]*UIObject, 1)
+			x.childOrder.Init()
+			d.emit(Patch{Op: PatchAdd, ParentId: sp.id, Index: i, Element: x})
+			sc = sp.children[bc.id]
+			d.index[bc.id] = sc
+		} else {
+			if sc.parent != sp || sc.childIndex() != i {
+				d.emit(Patch{Op: PatchMove, Id: sc.id, ParentId: sp.id, Index: i})
+			}
+			d.diffFields(sc, bc)
+		}
+		d.diffChildren(sc, bc)
+	}
+}
+
+func (d *differ) hasIndexedDescendant(uio *UIObject) bool {
+	found := false
+	uio.walk(func(x *UIObject, depth int) WalkAction {
+		if _, ok := d.index[x.id]; ok {
+			found = true
+			return WalkStop
+		}
+		return WalkContinue
+	}, 0)
+	return found
+}
+
+// Compare the element fields and attributes.
+func (d *differ) diffFields(sc, b *UIObject) {
+	if sc.contentType != b.contentType {
+		d.emit(Patch{Op: PatchContentType, Id: sc.id, Value: b.contentType})
+	}
+	if sc.class != b.class {
+		d.emit(Patch{Op: PatchClass, Id: sc.id, Value: b.class})
+	}
+	if sc.text != b.text {
+		d.emit(Patch{Op: PatchText, Id: sc.id, Value: b.text})
+	}
+	var names []string
+	for k := range sc.attrs {
+		if _, ok := b.attrs[k]; !ok {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		d.emit(Patch{Op: PatchRemoveAttribute, Id: sc.id, Name: k})
+	}
+	names = names[:0]
+	for k, v := range b.attrs {
+		if x, ok := sc.attrs[k]; !ok || x != v {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		d.emit(Patch{Op: PatchAttribute, Id: sc.id, Name: k, Value: b.attrs[k]})
+	}
+}
+
+// The position of the element in the parent's ordered children, or -1 without a parent.
+func (he *UIObject) childIndex() int {
+	if he.parent == nil {
+		return -1
+	}
+	i := 0
+	for l := he.parent.childOrder.Front(); l != nil; l, i = l.Next(), i+1 {
+		if l.Value.(*UIObject) == he {
+			return i
+		}
+	}
+	return -1
+}
+
+// Change the tree in place by applying the patches in order, e.g. the result of Diff().
+// Elements are found by id in the tree, including the element itself. Added elements are copied
+// from the patch, so the same patches can be applied to more than one tree.
+// Returns ErrNotFound if an element in a patch doesn't exist. Patches before the error are applied.
+func (he *UIObject) Apply(patches []Patch) error {
+	for _, p := range patches {
+		if err := he.applyPatch(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (he *UIObject) applyPatch(p Patch) error {
+	find := func(id string) (*UIObject, error) {
+		if x := he.SearchChildrenById(id); x != nil {
+			return toUIObject(x), nil
+		}
+		return nil, errorf(fmt.Sprintf("Apply %s patch, no element with id %q", p.Op, id), ErrNotFound)
+	}
+
+	switch p.Op {
+	case PatchAdd, PatchMove:
+		parent, err := find(p.ParentId)
+		if err != nil {
+			return err
+		}
+		var x *UIObject
+		if p.Op == PatchAdd {
+			if p.Element == nil {
+				return errorf("Apply add patch without an element", ErrNotFound)
+			}
+			x = p.Element.DeepCopy()
+		} else if x, err = find(p.Id); err != nil {
+			return err
+		}
+		// A moved element is detached before it is inserted, so Index is its final position.
+		return parent.InsertChildAt(p.Index, x)
+	}
+
+	x, err := find(p.Id)
+	if err != nil {
+		return err
+	}
+	switch p.Op {
+	case PatchRemove:
+		x.DetachFromParent()
+	case PatchAttribute:
+		if p.Name == "id" {
+			x.SetId(p.Value)
+		} else {
+			x.AddAttribute(p.Name, p.Value)
+		}
+	case PatchRemoveAttribute:
+		x.RemoveAttribute(p.Name)
+	case PatchClass:
+		x.SetClasses(p.Value)
+	case PatchText:
+		x.SetText(p.Value)
+	case PatchContentType:
+		x.SetContentType(p.Value)
+	default:
+		return errorf(fmt.Sprintf("Apply patch with unknown op %q", p.Op), ErrNotFound)
+	}
+	return nil
+}
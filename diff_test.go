@@ -0,0 +1,73 @@
+package goui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func treeJSON(t *testing.T, uio *UIObject) string {
+	b, err := json.Marshal(uio)
+	if err != nil {
+		t.Fatalf("Error on MarshalJSON: %s.\n", err)
+	}
+	return string(b)
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		a, _ := NewElementFromJSON(testFormJSON)
+		b := a.DeepCopy()
+		patches := Diff(a, b)
+		gotestutil.AssertEqual(t, len(patches), 0, "Expected no patches for equal trees. Actual: %v.", patches)
+	})
+
+	t.Run("A2", func(t *testing.T) {
+		a, _ := NewElementFromJSON(testFormJSON)
+		b := a.DeepCopy()
+		b.SearchChildrenById("ok").SetText("OK").AddCssClass("active").AddAttribute("disabled", "disabled")
+		b.SearchChildrenById("f1").RemoveAttribute("method")
+		patches := Diff(a, b)
+		ops := []PatchOp{}
+		for _, p := range patches {
+			ops = append(ops, p.Op)
+		}
+		gotestutil.AssertEqual(t, ops, []PatchOp{PatchRemoveAttribute, PatchClass, PatchText, PatchAttribute},
+			"Unexpected patches. Actual: %v.", patches)
+	})
+
+	t.Run("A3", func(t *testing.T) {
+		a, _ := NewElementFromJSON(testFormJSON)
+		b := a.DeepCopy()
+		b.SearchChildrenById("save").MoveTo(b.SearchChildrenById("f2"))
+		b.SearchChildrenById("grp").RemoveChild("cancel")
+		b.SearchChildrenById("f1").InsertChildAt(0, NewElement("p", "intro", "", "Hello"))
+		fs := NewElement("fieldset", "fs", "", "")
+		fs.AddChild(NewElement("legend", "lg", "", "Login"))
+		b.SearchChildrenById("f1").AddChild(fs)
+		b.SearchChildrenById("email").MoveTo(fs)
+
+		patches := Diff(a, b)
+		err := a.Apply(patches)
+		gotestutil.AssertNil(t, err, "Expected nil error on Apply.")
+		gotestutil.AssertStringsEqual(t, treeJSON(t, a), treeJSON(t, b), "Expected equal trees after Apply.")
+		gotestutil.AssertTrue(t, a.SearchChildrenById("cancel") == nil, "Expected element \"cancel\" removed.")
+	})
+
+	t.Run("A4", func(t *testing.T) {
+		a := newTestMenu()
+		b := newTestMenu()
+		b.SetChildOrder("c", "a")
+		b.SetId("menu2")
+		a.Apply(Diff(a, b))
+		gotestutil.AssertStringsEqual(t, treeJSON(t, a), treeJSON(t, b), "Expected equal trees after Apply.")
+	})
+}
+
+func TestUIObject_Apply(t *testing.T) {
+	m := newTestMenu()
+	err := m.Apply([]Patch{{Op: PatchText, Id: "a", Value: "A"}, {Op: PatchRemove, Id: "dummy"}})
+	gotestutil.AssertNotNil(t, err, "Expected error on Apply for id \"dummy\".")
+	gotestutil.AssertStringsEqual(t, m.GetChildById("a").Text(), "A", "Expected text patch applied.")
+}
@@ -0,0 +1,264 @@
+package goui
+
+import (
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Forms built from input elements, and binding a submitted request back into the form.
+//
+
+// Maximum memory used for multipart form data before files are stored on disk.
+const formMaxMemory = 32 << 20
+
+// The class of the elements that hold field error messages.
+const FieldErrorClass = "field-error"
+
+// A <form> element. Inputs, selects and textareas with a "name" attribute anywhere in the form
+// are its fields.
+type Form struct {
+	*UIObject
+	// Uploaded files from the last Bind, by field name.
+	files map[string][]*multipart.FileHeader
+}
+
+// Create a form that submits to the action URL with the method, e.g. "post".
+func NewForm(id, action, method string) *Form {
+	f := &Form{UIObject: NewElement(ContentTypeForm, id, "", "")}
+	f.AddAttribute("action", action)
+	f.AddAttribute("method", strings.ToLower(method))
+	return f
+}
+
+// Use an existing element, e.g. one read with NewElementFromJSON, as a form.
+func AsForm(ui HTMLElementWriter) *Form {
+	return &Form{UIObject: toUIObject(ui)}
+}
+
+// Set the form encoding for file uploads, multipart/form-data.
+func (f *Form) Multipart() *Form {
+	f.AddAttribute("enctype", "multipart/form-data")
+	return f
+}
+
+// Reports whether the element is a form field: an element with a name that submits a value.
+func isFormField(uio *UIObject) bool {
+	if len(uio.attrs["name"]) == 0 {
+		return false
+	}
+	switch tagForContentType(uio.contentType).name {
+	case "input", "select", "textarea", "button":
+		return true
+	}
+	return false
+}
+
+// Return the field elements with the name, in order. Radio groups and checkbox lists have several.
+func (f *Form) Fields(name string) []HTMLElementWriter {
+	var x []HTMLElementWriter
+	f.walk(func(uio *UIObject, depth int) WalkAction {
+		if isFormField(uio) && uio.attrs["name"] == name {
+			x = append(x, uio)
+		}
+		return WalkContinue
+	}, 0)
+	return x
+}
+
+// Return the field names of the form, in order, without duplicates.
+func (f *Form) FieldNames() []string {
+	var x []string
+	f.walk(func(uio *UIObject, depth int) WalkAction {
+		if isFormField(uio) && !containsString(x, uio.attrs["name"]) {
+			x = append(x, uio.attrs["name"])
+		}
+		return WalkContinue
+	}, 0)
+	return x
+}
+
+func (f *Form) hasFileInput() bool {
+	found := false
+	f.walk(func(uio *UIObject, depth int) WalkAction {
+		if uio.contentType == ContentInputFile {
+			found = true
+			return WalkStop
+		}
+		return WalkContinue
+	}, 0)
+	return found
+}
+
+// Read the submitted values for the form fields from the request, and set them on the fields so
+// the form re-renders with the submitted values. Text inputs get a value attribute, checkboxes and
+// radio buttons are checked if their value was submitted, options of a select are selected, and
+// textareas get the value. Passwords and disabled fields are not filled in. Files of a multipart
+// request are available from Files(). Field errors from an earlier Bind are cleared.
+//
+// Returns the submitted values for the form field names only. A GET form reads the URL query, other
+// methods read the request body.
+func (f *Form) Bind(r *http.Request) (url.Values, error) {
+	var err error
+	if f.hasFileInput() || strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		err = r.ParseMultipartForm(formMaxMemory)
+		if err == http.ErrNotMultipart {
+			err = r.ParseForm()
+		}
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		return nil, errorf("Error on Bind.", err)
+	}
+
+	submitted := r.PostForm
+	if strings.EqualFold(f.attrs["method"], http.MethodGet) || len(f.attrs["method"]) == 0 {
+		submitted = r.URL.Query()
+	}
+	f.files = make(map[string][]*multipart.FileHeader, 1)
+	if r.MultipartForm != nil {
+		for k, v := range r.MultipartForm.File {
+			f.files[k] = v
+		}
+	}
+
+	f.ClearErrors()
+	values := make(url.Values, 1)
+	for _, name := range f.FieldNames() {
+		v, ok := submitted[name]
+		if ok {
+			values[name] = v
+		}
+		f.bindField(name, v)
+	}
+	return values, nil
+}
+
+// Set the submitted values on the fields with the name.
+func (f *Form) bindField(name string, values []string) {
+	next := 0
+	for _, x := range f.Fields(name) {
+		uio := toUIObject(x)
+		if _, ok := uio.attrs["disabled"]; ok {
+			// Disabled fields are not submitted, so they keep their values.
+			continue
+		}
+		switch tag := tagForContentType(uio.contentType); {
+		case uio.contentType == ContentInputCheckbox || uio.contentType == ContentInputRadio:
+			setBoolAttribute(uio, "checked", containsString(values, fieldValue(uio, "on")))
+		case tag.name == "select":
+			uio.walk(func(o *UIObject, depth int) WalkAction {
+				if tagForContentType(o.contentType).name == "option" {
+					setBoolAttribute(o, "selected", containsString(values, fieldValue(o, o.text)))
+				}
+				return WalkContinue
+			}, 0)
+		case uio.contentType == ContentInputFile || uio.contentType == ContentInputPassword:
+			// Browsers don't allow file values to be set, and passwords are not sent back.
+		case tag.name == "button" || !inputHasValue(uio.contentType):
+			// Button captions are not submitted values.
+		default:
+			if next < len(values) {
+				uio.attrs["value"] = values[next]
+			} else {
+				delete(uio.attrs, "value")
+			}
+			next++
+		}
+	}
+}
+
+// Inputs whose value is entered by the user. The value of buttons is their caption.
+func inputHasValue(contentType string) bool {
+	switch contentType {
+	case ContentInputButton, ContentInputSubmit, ContentInputReset, ContentInputImage:
+		return false
+	}
+	return true
+}
+
+// The value submitted for a field, or the default if it has no value attribute.
+func fieldValue(uio *UIObject, def string) string {
+	if v, ok := uio.attrs["value"]; ok {
+		return v
+	}
+	return def
+}
+
+func setBoolAttribute(uio *UIObject, name string, on bool) {
+	if on {
+		uio.AddAttribute(name, "")
+	} else {
+		delete(uio.attrs, name)
+	}
+}
+
+// Return the files uploaded for a file input by the last Bind.
+func (f *Form) Files(name string) []*multipart.FileHeader {
+	return f.files[name]
+}
+
+// Attach an error message to the field with the name. The message is an element with content type
+// ContentTypeFieldError with class FieldErrorClass and the theme ThemeFieldError classes, added after
+// the field (after the last field of a radio group). The field is marked with aria-invalid,
+// aria-describedby and the theme ThemeFieldInvalid classes.
+// Returns ErrNotFound if the form has no field with the name.
+func (f *Form) SetFieldError(name, msg string) error {
+	fields := f.Fields(name)
+	if len(fields) == 0 {
+		return errorf("SetFieldError, no field named "+name, ErrNotFound)
+	}
+	last := toUIObject(fields[len(fields)-1])
+	errId := SelectorSafeId(last.id + "-error")
+	if e := f.SearchChildrenById(errId); e != nil {
+		e.SetText(msg)
+		return nil
+	}
+	for _, x := range fields {
+		x.SetAria("invalid", true).SetAria("describedby", errId)
+		x.AddThemeClass(ThemeFieldInvalid)
+	}
+	e := NewElement(ContentTypeFieldError, errId, FieldErrorClass, msg)
+	e.theme = last.currentTheme()
+	e.AddThemeClass(ThemeFieldError)
+	if last.parent == nil {
+		return f.AppendChild(e)
+	}
+	return last.parent.InsertAfter(last.id, e)
+}
+
+// Return the field error messages, by field name.
+func (f *Form) FieldErrors() map[string]string {
+	x := make(map[string]string, 1)
+	for _, name := range f.FieldNames() {
+		fields := f.Fields(name)
+		errId := SelectorSafeId(fields[len(fields)-1].Id() + "-error")
+		if e := f.SearchChildrenById(errId); e != nil && e.ContentType() == ContentTypeFieldError {
+			x[name] = e.Text()
+		}
+	}
+	return x
+}
+
+// Remove all field error messages, and the aria-invalid marks and ThemeFieldInvalid classes on the
+// fields.
+func (f *Form) ClearErrors() {
+	var errs []*UIObject
+	f.walk(func(uio *UIObject, depth int) WalkAction {
+		if uio.contentType == ContentTypeFieldError {
+			errs = append(errs, uio)
+			return WalkSkipChildren
+		}
+		if isFormField(uio) {
+			delete(uio.attrs, "aria-invalid")
+			delete(uio.attrs, "aria-describedby")
+			uio.RemoveThemeClass(ThemeFieldInvalid)
+		}
+		return WalkContinue
+	}, 0)
+	for _, e := range errs {
+		e.DetachFromParent()
+	}
+}
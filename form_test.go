@@ -0,0 +1,112 @@
+package goui
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func newTestForm(method string) *Form {
+	f := NewForm("signup", "/signup", method)
+	f.AddChild(NewEmailInput("email"))
+	f.AddChild(NewPasswordInput("pwd").Value("secret"))
+	f.AddChild(NewCheckboxInput("news", "yes"))
+	f.AddChild(NewRadioInput("size", "S"))
+	f.AddChild(NewRadioInput("size", "L").Checked(true))
+	f.AddChild(NewSubmitInput("go", "Sign up"))
+	return f
+}
+
+func postRequest(values url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestFormBind(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		f := newTestForm("post")
+		v, err := f.Bind(postRequest(url.Values{"email": {"a@b.c"}, "pwd": {"x"}, "news": {"yes"},
+			"size": {"S"}, "other": {"1"}}))
+		gotestutil.AssertNil(t, err, "Unexpected error on Bind. %v", err)
+		gotestutil.AssertStringsEqual(t, v.Get("email"), "a@b.c", "Expected email value returned.")
+		gotestutil.AssertEmptyString(t, v.Get("other"), "Expected only form fields returned.")
+
+		gotestutil.AssertStringsEqual(t, f.GetChildById("email").GetAttribute("value"), "a@b.c",
+			"Expected email value set.")
+		gotestutil.AssertStringsEqual(t, f.GetChildById("pwd").GetAttribute("value"), "secret",
+			"Expected password not refilled.")
+		_, checked := toUIObject(f.GetChildById("news-yes")).attrs["checked"]
+		gotestutil.AssertTrue(t, checked, "Expected checkbox checked.")
+		_, checked = toUIObject(f.GetChildById("size-S")).attrs["checked"]
+		gotestutil.AssertTrue(t, checked, "Expected radio S checked.")
+		_, checked = toUIObject(f.GetChildById("size-L")).attrs["checked"]
+		gotestutil.AssertFalse(t, checked, "Expected radio L unchecked.")
+		gotestutil.AssertStringsEqual(t, f.GetChildById("go").Text(), "Sign up",
+			"Expected submit caption unchanged.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		f := newTestForm("get")
+		r := httptest.NewRequest(http.MethodGet, "/signup?email=q%40r.s", nil)
+		v, err := f.Bind(r)
+		gotestutil.AssertNil(t, err, "Unexpected error on Bind. %v", err)
+		gotestutil.AssertStringsEqual(t, v.Get("email"), "q@r.s", "Expected email from the query.")
+		_, checked := toUIObject(f.GetChildById("size-L")).attrs["checked"]
+		gotestutil.AssertFalse(t, checked, "Expected radio L unchecked when size isn't submitted.")
+	})
+}
+
+func TestFormBindMultipart(t *testing.T) {
+	f := NewForm("up", "/up", "post").Multipart()
+	f.AddChild(NewTextInput("title"))
+	f.AddChild(NewFileInput("photo"))
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("title", "Cat")
+	fw, _ := mw.CreateFormFile("photo", "cat.png")
+	fw.Write([]byte("png"))
+	mw.Close()
+	r := httptest.NewRequest(http.MethodPost, "/up", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	_, err := f.Bind(r)
+	gotestutil.AssertNil(t, err, "Unexpected error on Bind. %v", err)
+	gotestutil.AssertStringsEqual(t, f.GetChildById("title").GetAttribute("value"), "Cat", "Expected title value set.")
+	files := f.Files("photo")
+	gotestutil.AssertTrue(t, len(files) == 1 && files[0].Filename == "cat.png", "Expected uploaded file. %v", files)
+}
+
+func TestFormFieldError(t *testing.T) {
+	f := newTestForm("post")
+	err := f.SetFieldError("email", "Email is required")
+	gotestutil.AssertNil(t, err, "Unexpected error on SetFieldError. %v", err)
+	err = f.SetFieldError("size", "Choose a size")
+	gotestutil.AssertNil(t, err, "Unexpected error on SetFieldError. %v", err)
+
+	gotestutil.AssertStringsEqual(t, strings.Join(childIds(f.UIObject), ","),
+		"email,email-error,pwd,news-yes,size-S,size-L,size-L-error,go", "Unexpected child order.")
+	gotestutil.AssertStringsEqual(t, f.GetChildById("email").Aria("describedby"), "email-error",
+		"Expected aria-describedby on the field.")
+	gotestutil.AssertStringsEqual(t, f.GetChildById("size-S").Aria("invalid"), "true",
+		"Expected aria-invalid on each radio.")
+	x := renderString(t, f.GetChildById("email-error").(*UIObject))
+	gotestutil.AssertStringsEqual(t, x, `<span id="email-error" class="field-error invalid-feedback">Email is required</span>`,
+		"Unexpected field error HTML. Actual: %s.", x)
+
+	errs := f.FieldErrors()
+	gotestutil.AssertStringsEqual(t, errs["size"], "Choose a size", "Expected size error.")
+
+	err = f.SetFieldError("missing", "x")
+	gotestutil.AssertTrue(t, err != nil && err.(*Error).Err == ErrNotFound, "Expected ErrNotFound. %v", err)
+
+	f.ClearErrors()
+	gotestutil.AssertEqual(t, len(f.FieldErrors()), 0, "Expected errors cleared.")
+	gotestutil.AssertEmptyString(t, f.GetChildById("email").Aria("invalid"), "Expected aria-invalid removed.")
+}
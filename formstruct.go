@@ -0,0 +1,411 @@
+package goui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Forms generated from Go structs. Each exported field becomes an input, configured by a goui tag.
+//
+// Example:
+//     type Signup struct {
+//         Email string    `goui:"label=Email,type=email_input,required"`
+//         Age   int       `goui:"label=Age,min=18,max=130"`
+//         Born  time.Time `goui:"label=Date of birth"`
+//         News  bool      `goui:"label=Send me news"`
+//         Token string    `goui:"-"`
+//     }
+//     form, err := FormFromStruct(&Signup{}, FormAction("/signup"))
+//     ...
+//     var s Signup
+//     err = DecodeForm(r, &s)
+//
+// Tag options are name, label, type (a ContentInput* value), placeholder, required, min, max, step,
+// minlength, maxlength and pattern. A pattern can't contain a comma. A field tagged "-" is left out.
+// The default input type is from the field type: time.Time is a date input, bool is a checkbox,
+// integers and floats are number inputs, and strings are text inputs.
+
+var (
+	// A struct field type has no matching input, or a tag option is not valid.
+	ErrStructField = errors.New("unsupported struct field")
+	// The value is not a struct, or for DecodeForm, a pointer to a struct.
+	ErrNotStruct = errors.New("value is not a struct")
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// An option of FormFromStruct.
+type FormOption func(*formOptions)
+
+type formOptions struct {
+	id, action, method, submit string
+}
+
+// Set the form id. The default is the struct type name, in lower case.
+func FormId(id string) FormOption {
+	return func(o *formOptions) { o.id = id }
+}
+
+// Set the form action URL.
+func FormAction(action string) FormOption {
+	return func(o *formOptions) { o.action = action }
+}
+
+// Set the form method. The default is "post".
+func FormMethod(method string) FormOption {
+	return func(o *formOptions) { o.method = method }
+}
+
+// Set the caption of the submit button. The default is "Save". Use "" for no submit button.
+func FormSubmit(text string) FormOption {
+	return func(o *formOptions) { o.submit = text }
+}
+
+// A struct field with an input, and the options from its tag.
+type structField struct {
+	index       []int
+	typ         reflect.Type
+	name        string
+	label       string
+	contentType string
+	opts        map[string]string
+}
+
+// Lower case the first letter of a field name, e.g. "FirstName" is "firstName".
+func lowerFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
+
+func parseStructTag(tag string) map[string]string {
+	opts := make(map[string]string, 1)
+	for _, o := range strings.Split(tag, ",") {
+		o = strings.TrimSpace(o)
+		if len(o) == 0 {
+			continue
+		}
+		if i := strings.IndexByte(o, '='); i >= 0 {
+			opts[strings.TrimSpace(o[:i])] = strings.TrimSpace(o[i+1:])
+		} else {
+			opts[o] = ""
+		}
+	}
+	return opts
+}
+
+// Return the fields of a struct type with inputs, in order. Fields of embedded structs are included.
+func parseStructFields(t reflect.Type, index []int) ([]structField, error) {
+	var x []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("goui")
+		if tag == "-" {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Type != timeType {
+			sub, err := parseStructFields(f.Type, idx)
+			if err != nil {
+				return nil, err
+			}
+			x = append(x, sub...)
+			continue
+		}
+		if len(f.PkgPath) > 0 {
+			continue
+		}
+		sf := structField{index: idx, typ: f.Type, name: lowerFirst(f.Name), label: f.Name,
+			opts: parseStructTag(tag)}
+		if sf.typ.Kind() == reflect.Ptr {
+			sf.typ = sf.typ.Elem()
+		}
+		if v, ok := sf.opts["name"]; ok && len(v) > 0 {
+			sf.name = v
+		}
+		if v, ok := sf.opts["label"]; ok {
+			sf.label = v
+		}
+		sf.contentType = sf.opts["type"]
+		if len(sf.contentType) == 0 {
+			sf.contentType = defaultInputType(sf.typ)
+		}
+		if _, ok := contentTags[sf.contentType]; !ok || tagForContentType(sf.contentType).name != "input" {
+			return nil, errorf(fmt.Sprintf("Field %q of type %s, input type %q", f.Name, f.Type, sf.contentType),
+				ErrStructField)
+		}
+		x = append(x, sf)
+	}
+	return x, nil
+}
+
+// Return the input type for a field type, or "" if there is none.
+func defaultInputType(t reflect.Type) string {
+	if t == timeType {
+		return ContentInputDate
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return ContentInputCheckbox
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return ContentInputNumber
+	case reflect.String:
+		return ContentInputText
+	}
+	return ""
+}
+
+// Create a form with an input for each field of a struct, or a pointer to a struct. The inputs
+// have the values of the fields, and the validation rules from the tags, so Form.Validate can
+// check a submission. Returns ErrNotStruct or ErrStructField if the struct has no form.
+func FormFromStruct(v interface{}, opts ...FormOption) (*UIObject, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, errorf(fmt.Sprintf("FormFromStruct %T", v), ErrNotStruct)
+	}
+	o := formOptions{id: strings.ToLower(rv.Type().Name()), method: "post", submit: "Save"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	fields, err := parseStructFields(rv.Type(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	f := NewForm(o.id, o.action, o.method)
+	for _, sf := range fields {
+		in, err := newStructInput(sf, rv.FieldByIndex(sf.index))
+		if err != nil {
+			return nil, err
+		}
+		if err := f.AppendChild(in); err != nil {
+			return nil, err
+		}
+	}
+	if len(o.submit) > 0 {
+		btn := NewSubmitInput("", o.submit)
+		btn.SetId(SelectorSafeId(o.id + "-submit"))
+		if err := f.AppendChild(btn); err != nil {
+			return nil, err
+		}
+	}
+	return f.UIObject, nil
+}
+
+// Create the input for a struct field, with the value of the field.
+func newStructInput(sf structField, fv reflect.Value) (*InputElement, error) {
+	in := NewInput(sf.contentType, sf.name)
+	in.Label(sf.label)
+	if v, ok := sf.opts["placeholder"]; ok {
+		in.Placeholder(v)
+	}
+	if v, ok := sf.opts["step"]; ok {
+		in.AddAttribute("step", v)
+	} else if k := sf.typ.Kind(); k == reflect.Float32 || k == reflect.Float64 {
+		in.AddAttribute("step", "any")
+	}
+	if err := addStructRules(sf, in); err != nil {
+		return nil, err
+	}
+
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return in, nil
+		}
+		fv = fv.Elem()
+	}
+	switch {
+	case sf.contentType == ContentInputCheckbox:
+		in.Value("true")
+		in.setBool("checked", fv.Kind() == reflect.Bool && fv.Bool())
+	case sf.contentType == ContentInputPassword || sf.contentType == ContentInputFile:
+		// Passwords are not sent to the browser, and file values can't be set.
+	case fv.Type() == timeType:
+		if t := fv.Interface().(time.Time); !t.IsZero() {
+			in.Value(FormatInputTime(sf.contentType, t))
+		}
+	default:
+		in.Value(fmt.Sprint(fv.Interface()))
+	}
+	return in, nil
+}
+
+// Add the validation rules from the tag options of a struct field.
+func addStructRules(sf structField, in *InputElement) error {
+	invalid := func(opt string) error {
+		return errorf(fmt.Sprintf("Field %q option %s=%q", sf.name, opt, sf.opts[opt]), ErrStructField)
+	}
+	if _, ok := sf.opts["required"]; ok {
+		in.AddRule(RequiredRule())
+	}
+	for _, opt := range []string{"minlength", "maxlength"} {
+		if v, ok := sf.opts[opt]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return invalid(opt)
+			}
+			if opt == "minlength" {
+				in.AddRule(MinLengthRule(n))
+			} else {
+				in.AddRule(MaxLengthRule(n))
+			}
+		}
+	}
+	if v, ok := sf.opts["pattern"]; ok {
+		in.AddRule(PatternRule(v))
+	}
+
+	min, hasMin := sf.opts["min"]
+	max, hasMax := sf.opts["max"]
+	if !hasMin && !hasMax {
+		return nil
+	}
+	if sf.typ == timeType {
+		var from, to time.Time
+		var err error
+		if hasMin {
+			if from, err = ParseInputTime(sf.contentType, min); err != nil {
+				return invalid("min")
+			}
+		}
+		if hasMax {
+			if to, err = ParseInputTime(sf.contentType, max); err != nil {
+				return invalid("max")
+			}
+		}
+		in.AddRule(DateRangeRule(sf.contentType, from, to))
+		return nil
+	}
+	lo, hi := math.Inf(-1), math.Inf(1)
+	var err error
+	if hasMin {
+		if lo, err = strconv.ParseFloat(min, 64); err != nil {
+			return invalid("min")
+		}
+	}
+	if hasMax {
+		if hi, err = strconv.ParseFloat(max, 64); err != nil {
+			return invalid("max")
+		}
+	}
+	in.AddRule(RangeRule(lo, hi))
+	return nil
+}
+
+// Fill a struct from a form submission. v is a pointer to a struct, and the values are read by
+// the field names of FormFromStruct. A GET or HEAD request reads the URL query, other methods read
+// the request body. Fields that were not submitted are unchanged, except for checkboxes, which
+// are false if they are not checked. Returns an error if a value can't be converted to the field type.
+func DecodeForm(r *http.Request, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return errorf(fmt.Sprintf("DecodeForm %T", v), ErrNotStruct)
+	}
+	fields, err := parseStructFields(rv.Elem().Type(), nil)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		err = r.ParseMultipartForm(formMaxMemory)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		return errorf("Error on DecodeForm.", err)
+	}
+	values := r.PostForm
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		values = r.URL.Query()
+	}
+
+	for _, sf := range fields {
+		fv := rv.Elem().FieldByIndex(sf.index)
+		s, ok := values[sf.name]
+		switch {
+		case sf.contentType == ContentInputFile:
+			continue
+		case sf.contentType == ContentInputCheckbox && !ok:
+			s = []string{"false"}
+		case !ok:
+			continue
+		}
+		if err := setStructField(sf, fv, s[0]); err != nil {
+			return errorf(fmt.Sprintf("Field %q value %q", sf.name, s[0]), err)
+		}
+	}
+	return nil
+}
+
+// Convert a submitted value to the type of a struct field, and set the field.
+// An empty value sets the zero value, or nil for a pointer. Strings are set as submitted, and
+// other values have spaces trimmed.
+func setStructField(sf structField, fv reflect.Value, s string) error {
+	if sf.typ.Kind() != reflect.String {
+		s = strings.TrimSpace(s)
+	}
+	if fv.Kind() == reflect.Ptr {
+		if len(s) == 0 {
+			fv.Set(reflect.Zero(fv.Type()))
+			return nil
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.New(sf.typ))
+		}
+		fv = fv.Elem()
+	}
+	if len(s) == 0 && fv.Kind() != reflect.String {
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	}
+	if fv.Type() == timeType {
+		t, err := ParseInputTime(sf.contentType, s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.Bool:
+		b := s == "on"
+		if !b {
+			var err error
+			if b, err = strconv.ParseBool(s); err != nil {
+				return err
+			}
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	case reflect.String:
+		fv.SetString(s)
+	default:
+		return ErrStructField
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package goui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+type testAudit struct {
+	Updated time.Time `goui:"type=hidden_input"`
+}
+
+type testSignup struct {
+	testAudit
+	Email    string    `goui:"label=Email,type=email_input,required"`
+	Password string    `goui:"type=password_input,minlength=8"`
+	Age      int       `goui:"label=Age,min=18,max=130"`
+	Score    *float64  `goui:"name=score"`
+	Born     time.Time `goui:"label=Date of birth,min=1900-01-01"`
+	News     bool      `goui:"label=Send me news"`
+	Token    string    `goui:"-"`
+	secret   string
+}
+
+func TestFormFromStruct(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		s := testSignup{Email: "a@b.c", Password: "pw", Age: 30, News: true,
+			Born: time.Date(1990, 5, 6, 0, 0, 0, 0, time.UTC)}
+		ui, err := FormFromStruct(&s, FormAction("/signup"))
+		gotestutil.AssertNil(t, err, "Unexpected error on FormFromStruct. %v", err)
+		gotestutil.AssertStringsEqual(t, strings.Join(childIds(ui), ","),
+			"updated,email,password,age,score,born,news,testsignup-submit", "Unexpected inputs.")
+
+		email := renderString(t, ui.GetChildById("email").(*UIObject))
+		gotestutil.AssertStringsEqual(t, email,
+			`<label for="email">Email</label><input type="email" id="email" name="email" required value="a@b.c">`,
+			"Unexpected email input HTML. Actual: %s.", email)
+		gotestutil.AssertEmptyString(t, ui.GetChildById("password").GetAttribute("value"), "Expected no password value.")
+		gotestutil.AssertStringsEqual(t, ui.GetChildById("age").GetAttribute("min"), "18", "Expected age min.")
+		gotestutil.AssertStringsEqual(t, ui.GetChildById("score").GetAttribute("step"), "any", "Expected float step.")
+		gotestutil.AssertStringsEqual(t, ui.GetChildById("born").GetAttribute("value"), "1990-05-06",
+			"Expected date value.")
+		_, checked := ui.GetChildById("news").(*UIObject).attrs["checked"]
+		gotestutil.AssertTrue(t, checked, "Expected news checked.")
+
+		errs := AsForm(ui).Validate(url.Values{"age": {"12"}})
+		gotestutil.AssertStringsEqual(t, errs["email"].Rule, "required", "Expected required email.")
+		gotestutil.AssertStringsEqual(t, errs["age"].Rule, "range", "Expected age range.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		_, err := FormFromStruct(struct{ Tags []string }{})
+		gotestutil.AssertTrue(t, err != nil && err.(*Error).Err == ErrStructField, "Expected ErrStructField. %v", err)
+		_, err = FormFromStruct(struct {
+			Age int `goui:"min=x"`
+		}{})
+		gotestutil.AssertTrue(t, err != nil && err.(*Error).Err == ErrStructField, "Expected ErrStructField. %v", err)
+		_, err = FormFromStruct("x")
+		gotestutil.AssertTrue(t, err != nil && err.(*Error).Err == ErrNotStruct, "Expected ErrNotStruct. %v", err)
+	})
+}
+
+func TestDecodeForm(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		s := testSignup{News: true, Token: "keep"}
+		values := url.Values{"email": {"a@b.c"}, "password": {" pw "}, "age": {"42"}, "score": {"1.5"},
+			"born": {"1990-05-06"}, "updated": {"2017-01-02"}}
+		r := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		err := DecodeForm(r, &s)
+		gotestutil.AssertNil(t, err, "Unexpected error on DecodeForm. %v", err)
+		gotestutil.AssertStringsEqual(t, s.Email, "a@b.c", "Unexpected email.")
+		gotestutil.AssertStringsEqual(t, s.Password, " pw ", "Expected strings unchanged.")
+		gotestutil.AssertEqual(t, s.Age, 42, "Unexpected age.")
+		gotestutil.AssertTrue(t, s.Score != nil && *s.Score == 1.5, "Unexpected score.")
+		gotestutil.AssertTrue(t, s.Born.Equal(time.Date(1990, 5, 6, 0, 0, 0, 0, time.UTC)), "Unexpected born.")
+		gotestutil.AssertTrue(t, s.Updated.Year() == 2017, "Expected embedded field decoded.")
+		gotestutil.AssertFalse(t, s.News, "Expected unchecked checkbox false.")
+		gotestutil.AssertStringsEqual(t, s.Token, "keep", "Expected skipped field unchanged.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		var s testSignup
+		r := httptest.NewRequest(http.MethodGet, "/signup?age=old", nil)
+		err := DecodeForm(r, &s)
+		gotestutil.AssertNotNil(t, err, "Expected error for an invalid number.")
+		err = DecodeForm(r, s)
+		gotestutil.AssertTrue(t, err != nil && err.(*Error).Err == ErrNotStruct, "Expected ErrNotStruct. %v", err)
+	})
+}
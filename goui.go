@@ -3,11 +3,12 @@ package goui
 import (
 	"strings"
 	"fmt"
-	"math/rand"
 	"html/template"
 	"container/list"
 	"encoding/json"
 	"log"
+	"io"
+	"bytes"
 )
 
 // Used for template errors that bubble up.
@@ -32,6 +33,17 @@ const (
 	ContentTypeMenu string = "menu"
 	ContentTypeImage string = "image"
 	ContentTypeSeparator string = "separator"
+	ContentTypeForm string = "form"
+	ContentTypeFieldError string = "field_error"
+	ContentTypeSelect string = "select"
+	ContentTypeOption string = "option"
+	ContentTypeOptGroup string = "optgroup"
+	ContentTypeTextarea string = "textarea"
+	ContentTypeDatalist string = "datalist"
+	ContentTypeBreadcrumbs string = "breadcrumbs"
+	ContentTypeJSONLD string = "jsonld"
+	ContentTypePicture string = "picture"
+	ContentTypeSource string = "source"
 
 	// Input types
 	ContentInputButton string = "button_input"
@@ -63,12 +75,38 @@ type AttributeInterface interface {
 	GetAttribute(k string) string
 	AddAttribute(attName string, attValue string) (HTMLElementWriter)
 	RemoveAttribute(attName string) (HTMLElementWriter)
+	SetData(key string, v interface{}) HTMLElementWriter
+	Data(key string) string
+	Dataset() map[string]string
+	SetAria(name string, v interface{}) HTMLElementWriter
+	Aria(name string) string
+	SetRole(role string) HTMLElementWriter
 }
 
 type ClassInterface interface {
 	Class() string
 	AddCssClass(className string) (HTMLElementWriter)
 	RemoveCssClass(className string) (HTMLElementWriter)
+	HasClass(className string) bool
+	ToggleClass(className string) (HTMLElementWriter)
+	ReplaceClass(oldName, newName string) (HTMLElementWriter)
+	ClassList() []string
+	SetClasses(classNames ...string) (HTMLElementWriter)
+	AddThemeClass(roles ...ThemeRole) (HTMLElementWriter)
+	RemoveThemeClass(roles ...ThemeRole) (HTMLElementWriter)
+}
+
+type StyleInterface interface {
+	StyleMap() StyleMap
+	SetStyleMap(sm StyleMap) HTMLElementWriter
+	SetStyle(prop, value string) HTMLElementWriter
+	RemoveStyle(prop string) HTMLElementWriter
+	Style(prop string) string
+}
+
+type ValidationInterface interface {
+	AddRule(rules ...Rule) HTMLElementWriter
+	Rules() []Rule
 }
 
 type IdInterface interface {
@@ -86,21 +124,46 @@ type ChildrenInterface interface {
 	ChildrenByOrder() []HTMLElementWriter
 	ChildCount() int
 	AddChild(ui HTMLElementWriter) HTMLElementWriter
+	AppendChild(ui HTMLElementWriter) error
 	SetChildOrder(thisId, beforeId string) HTMLElementWriter
+	RemoveChild(id string) error
+	ReplaceChild(id string, ui HTMLElementWriter) error
+	InsertChildAt(index int, ui HTMLElementWriter) error
+	InsertAfter(afterId string, ui HTMLElementWriter) error
+	MoveTo(newParent HTMLElementWriter) error
+	DetachFromParent() HTMLElementWriter
 	GetChildById(id string) HTMLElementWriter
 	SearchChildrenById(id string) HTMLElementWriter
 }
 
+type ParentInterface interface {
+	Parent() HTMLElementWriter
+	Ancestors() []HTMLElementWriter
+	Closest(contentType string) HTMLElementWriter
+	Path() []string
+}
+
 type HTMLElementWriter interface {
 	GetHTMLElementWriter() HTMLElementWriter
 	SetContentType(s string) HTMLElementWriter
 	ContentType() string
 	GetContentByType(t string) []HTMLElementWriter
+	Query(selector string) []HTMLElementWriter
+	QueryE(selector string) ([]HTMLElementWriter, error)
+	QueryOne(selector string) HTMLElementWriter
+	Render(w io.Writer) error
+	Clone() HTMLElementWriter
+	Walk(fn func(el HTMLElementWriter, depth int) WalkAction)
+	WalkPostOrder(fn func(el HTMLElementWriter, depth int) WalkAction)
+	Transform(fn func(el HTMLElementWriter) HTMLElementWriter) HTMLElementWriter
 	ClassInterface
 	AttributeInterface
+	StyleInterface
+	ValidationInterface
 	IdInterface
 	TextInterface
 	ChildrenInterface
+	ParentInterface
 }
 
 func init() {
@@ -114,29 +177,31 @@ type AttributeMap map[string]string
 
 // Render the attributes into a string suitable for use inside an HTML element tag in a template.
 // The template call call the String() function, e.e. {{Attr.String}}
+// Attributes are in name order and values are escaped. Boolean attributes, e.g. disabled, are
+// rendered by name only, and are left out if the value is "false". Invalid names are left out.
 func (attr AttributeMap) String() template.HTML {
-	x := ""
-	for k, v := range attr {
-		x = x + " " + k + "=\"" + v + "\""
-	}
-	return template.HTML(x)
+	var b bytes.Buffer
+	attr.render(&errWriter{w: &b})
+	return template.HTML(b.String())
 }
 
 // Structure for UIObjects via JSON
 // Example: `{"text":"A", "id":"button1", "type":"button", "class":"btn-primary", "attributes":{"href":"/"}}`*/
 type elementStruct struct {
 	// Text is typically for labels, or can be used in templates for various means.
-	Text       string `json:"text"`
+	Text       string `json:"text,omitempty"`
 	// Id is the element id on the page.
 	Id         string `json:"id"`
+	// HideId is set if the id is only the key of the element in its parent, and is not rendered.
+	HideId     bool `json:"hideId,omitempty"`
 	// The elemen type. This can be one of the ContentType* or ContentInput* values or arbitrary. Used in templates.
 	Etype      string `json:"type"`
 	// A class name that can be added to the item in the rendered template, and progammatically changed.
-	ClassName  string `json:"class"`
+	ClassName  string `json:"class,omitempty"`
 	// Additional HTML tag attributes. Used in templates.
-	Attributes AttributeMap `json:"attributes"`
+	Attributes AttributeMap `json:"attributes,omitempty"`
 	// Child elements of this element. E.g. items in a menu. content elements in a composite panel element.
-	Children   []elementStruct `json:"children"`
+	Children   []elementStruct `json:"children,omitempty"`
 }
 
 // To be used within a template
@@ -153,23 +218,39 @@ type UIObject struct {
 	children    map[string]*UIObject
 	// Ordered list of children. Order set by the when AddChild() is called, or SetOrder()
 	childOrder  *list.List
+	// The object this is a child of. Nil for the root of a hierarchy.
+	parent      *UIObject
+	// Validation rules for a form field. See AddRule.
+	rules       []Rule
+	// For a copy made with CloneForWrite, the elements of the original that the copy shares.
+	sharedNodes map[*UIObject]bool
+	// Generator for the ids of descendants, from the UIContext that created the element.
+	idGen       IdGenerator
+	// The id is only the key of the element among the children of its parent, and is not rendered.
+	hideId      bool
+	// The theme roles added by AddThemeClass, so RemoveThemeClass keeps classes of the other roles.
+	themeRoles  []ThemeRole
+	// The theme of the UIContext that created the element.
+	theme       Theme
 }
 
 func NewElement(cType string, id string, className string, text string) *UIObject {
 	h := &UIObject{contentType: cType,
 		id: id,
-		class: className,
 		text: text,
 		children: make(map[string]*UIObject, 1),
 		attrs: make(AttributeMap, 1),
 		childOrder: list.New(),
 	}
+	h.SetClasses(className)
 	return h
 }
 
 // JSON format for creating an element.
-// Format is a JSON object with fields: "text", "id", "class", "type", and "attributes".
+// Format is a JSON object with fields: "text", "id", "hideId", "class", "type", "attributes" and
+// "children". If hideId is true, the id is not rendered, e.g. for the rows and cells of a DataTable.
 // Attributes is an AttributeMap (map[string]string) of additional HTML attributes.
+// Children is an array of objects in the same format, to any depth, and are added in array order.
 //
 // The "type" attribute can be one of the ContentType* or ContentInput* values, or another arbitrary values. The type
 // is used by templates in rendering.
@@ -181,13 +262,73 @@ func NewElementFromJSON(s string) (*UIObject, error) {
 		return nil, err
 	}
 
-	uio := NewElement(eBuf.Etype, eBuf.Id, eBuf.ClassName, eBuf.Text).AddAttributeMap(eBuf.Attributes)
+	uio, err := newElementFromStruct(eBuf)
+	if err != nil {
+		log.Printf("goui.NewElementFromJSON: %s", err)
+		return nil, err
+	}
+	return uio, nil
+}
+
+// Create an element, and all descendants, from the parsed JSON structure.
+// Returns ErrDuplicateId if two children of an element have the same id.
+func newElementFromStruct(es elementStruct) (*UIObject, error) {
+	uio := NewElement(es.Etype, es.Id, es.ClassName, es.Text)
+	uio.hideId = es.HideId
+	uio.AddAttributeMap(es.Attributes)
+	for _, v := range es.Children {
+		c, err := newElementFromStruct(v)
+		if err != nil {
+			return nil, err
+		}
+		if err = uio.AppendChild(c); err != nil {
+			return nil, err
+		}
+	}
+	return uio, nil
+}
+
+// Create the JSON structure for the element, and all descendants. Children are in order.
+func (he *UIObject) elementStruct() elementStruct {
+	es := elementStruct{
+		Text:       he.text,
+		Id:         he.id,
+		HideId:     he.hideId,
+		Etype:      he.contentType,
+		ClassName:  he.class,
+		Attributes: he.attrs,
+	}
+	for l := he.childOrder.Front(); l != nil; l = l.Next() {
+		es.Children = append(es.Children, l.Value.(*UIObject).elementStruct())
+	}
+	return es
+}
+
+// Encode the element, and all descendants, in the format read by NewElementFromJSON.
+// Children are encoded in the ChildrenByOrder sequence.
+// Implements the json.Marshaler interface
+func (he *UIObject) MarshalJSON() ([]byte, error) {
+	return json.Marshal(he.elementStruct())
+}
 
-	for _, v := range eBuf.Children {
-		cuio := NewElement(v.Etype, v.Id, v.ClassName, v.Text).AddAttributeMap(v.Attributes)
-		uio.AddChild(cuio)
+// Decode an element, and all descendants, from the format read by NewElementFromJSON.
+// Any existing attributes and children of the element are replaced.
+// Implements the json.Unmarshaler interface
+func (he *UIObject) UnmarshalJSON(b []byte) error {
+	var eBuf elementStruct
+	if err := json.Unmarshal(b, &eBuf); err != nil {
+		return err
 	}
-	return uio.(*UIObject), nil
+	uio, err := newElementFromStruct(eBuf)
+	if err != nil {
+		return err
+	}
+	uio.parent = he.parent
+	*he = *uio
+	for _, c := range he.children {
+		c.parent = he
+	}
+	return nil
 }
 
 // Returns the HTMLElementWriter interface from a *UIObject.
@@ -209,8 +350,19 @@ func (he UIObject) Text() string {
 }
 
 // Sets the HTML "id" attribute. This is used in templates.
+// If the object is a child, the parent's lookup by id is updated. If the parent already has another
+// child with the id, ErrDuplicateId is logged and the id is unchanged.
 // Implements Id interface
 func (he *UIObject) SetId(i string) HTMLElementWriter {
+	if p := he.parent; p != nil && p.children[he.id] == he {
+		if x, ok := p.children[i]; ok && x != he {
+			log.Printf("goui.SetId: %s", errorf(fmt.Sprintf("Element %q already has a child with id %q", p.id, i),
+				ErrDuplicateId))
+			return he
+		}
+		delete(p.children, he.id)
+		p.children[i] = he
+	}
 	he.id = i
 	return he
 }
@@ -228,19 +380,77 @@ func (he UIObject) Class() string {
 	return he.class
 }
 
-// Add a CSS class to an object.
+// Add a CSS class to an object. Classes already on the object are not added again.
+// The className can contain several space separated classes, e.g. "btn btn-primary".
 // Implements the Class interface
 func (he *UIObject) AddCssClass(className string) (HTMLElementWriter) {
-	if !strings.Contains(he.class, className) {
-		he.class = strings.TrimSpace(he.class) + " " + strings.TrimSpace(className)
-	}
+	he.SetClasses(append(he.ClassList(), strings.Fields(className)...)...)
 	return he
 }
 
-// Removes a CSS class from an object. This removes all instances of the className string.
+// Removes a CSS class from an object. Only whole class names are removed, so removing "btn"
+// leaves "btn-primary". The className can contain several space separated classes.
 // Implements the Class interface
 func (he *UIObject) RemoveCssClass(className string) (HTMLElementWriter) {
-	he.class = strings.TrimSpace(strings.Replace(he.class, strings.TrimSpace(className), "", -1))
+	remove := strings.Fields(className)
+	var x []string
+	for _, c := range he.ClassList() {
+		if !containsString(remove, c) {
+			x = append(x, c)
+		}
+	}
+	he.SetClasses(x...)
+	return he
+}
+
+// Reports whether the object has the CSS class.
+// Implements the Class interface
+func (he *UIObject) HasClass(className string) bool {
+	return containsString(he.ClassList(), className)
+}
+
+// Add the CSS class if the object doesn't have it, otherwise remove it.
+// Implements the Class interface
+func (he *UIObject) ToggleClass(className string) (HTMLElementWriter) {
+	if he.HasClass(className) {
+		return he.RemoveCssClass(className)
+	}
+	return he.AddCssClass(className)
+}
+
+// Replace the CSS class oldName with newName, in the same position. If the object doesn't have
+// oldName, nothing is changed.
+// Implements the Class interface
+func (he *UIObject) ReplaceClass(oldName, newName string) (HTMLElementWriter) {
+	x := he.ClassList()
+	for i, c := range x {
+		if c == oldName {
+			x[i] = newName
+			he.SetClasses(x...)
+			break
+		}
+	}
+	return he
+}
+
+// Returns the CSS classes of the object, in the order they were added.
+// Implements the Class interface
+func (he *UIObject) ClassList() []string {
+	return strings.Fields(he.class)
+}
+
+// Replace all CSS classes of the object. Duplicate classes are removed, keeping the first.
+// Implements the Class interface
+func (he *UIObject) SetClasses(classNames ...string) (HTMLElementWriter) {
+	var x []string
+	for _, v := range classNames {
+		for _, c := range strings.Fields(v) {
+			if !containsString(x, c) {
+				x = append(x, c)
+			}
+		}
+	}
+	he.class = strings.Join(x, " ")
 	return he
 }
 
@@ -267,7 +477,19 @@ func (he *UIObject) GetAttribute(attrName string) string {
 // Add a HTML attribute to an object. If the attribute already exists, it is replaced.
 // Templates can retrieve an attribute using .GetAttribute pipeline (See GetAttribute)
 // Implements Attribute interface
+// An invalid attribute name is logged, and the attribute is not added. For the ContentInput* types,
+// an attribute that is not valid for the input type is also logged and not added, see
+// InputAttributeAllowed.
+// For boolean attributes, e.g. "disabled", use the value "" or the attribute name.
 func (he *UIObject) AddAttribute(attName string, attValue string) (HTMLElementWriter) {
+	if !ValidAttributeName(attName) {
+		log.Printf("goui.AddAttribute: invalid attribute name %q", attName)
+		return he
+	}
+	if isInputType(he.contentType) && !InputAttributeAllowed(he.contentType, attName) {
+		log.Printf("goui.AddAttribute: attribute %q is not valid for %s", attName, he.contentType)
+		return he
+	}
 	he.attrs[attName] = attValue
 	return he
 }
@@ -275,7 +497,7 @@ func (he *UIObject) AddAttribute(attName string, attValue string) (HTMLElementWr
 // Add attributes from an AttributeMap.
 func (he *UIObject) AddAttributeMap(a AttributeMap) (HTMLElementWriter) {
 	for k, v := range a {
-		he.attrs[k] = v
+		he.AddAttribute(k, v)
 	}
 	return he
 }
@@ -288,9 +510,19 @@ func (he *UIObject) RemoveAttribute(attName string) HTMLElementWriter {
 }
 
 // Set the content type. This is any arbitrary text that can be used to establish a content type
+// For one of the ContentInput* types, attributes the input type doesn't allow, see
+// InputAttributeAllowed, are removed, and each removed attribute is logged.
 // Implements the HTMLElementWriter interface
 func (he *UIObject) SetContentType(s string) HTMLElementWriter {
 	he.contentType = s
+	if isInputType(s) {
+		for k := range he.attrs {
+			if !InputAttributeAllowed(s, k) {
+				log.Printf("goui.SetContentType: attribute %q is not valid for %s, removed", k, s)
+				delete(he.attrs, k)
+			}
+		}
+	}
 	return he
 }
 
@@ -378,16 +610,30 @@ func (uio *UIObject) SearchChildrenById(id string) HTMLElementWriter {
 // Add a child object to a parent object. This creates a hierarchy of data objects.
 // If a UI control "panel" is a content block of header/title, text, and date,
 // The panel object has three children, one child for each.
+// If the child already has a parent, it is first removed from that parent.
+// A child without an id is given one by the context IdGenerator. If the parent already has a child
+// with the same id, the error is logged and the child is not added; the error is not returned, so
+// use AppendChild, which returns ErrDuplicateId, when the id may be taken.
 // Implements the Child Interface.
 func (he *UIObject) AddChild(ui HTMLElementWriter) HTMLElementWriter {
-	if len(ui.Id()) == 0 {
-		ui.SetId(generateId(ui.ContentType(), 100))
+	if err := he.AppendChild(ui); err != nil {
+		log.Printf("goui.AddChild: %s", err)
 	}
-	he.children[ui.Id()] = ui.(*UIObject)
-	he.childOrder.PushBack(ui)
 	return he
 }
 
+// Add a child object to the end of the ordered children, as AddChild.
+// Returns ErrDuplicateId if the parent already has a child with the same id.
+// Implements the Child Interface.
+func (he *UIObject) AppendChild(ui HTMLElementWriter) error {
+	c, err := he.adopt(ui)
+	if err != nil {
+		return err
+	}
+	he.childOrder.PushBack(c)
+	return nil
+}
+
 // Add multiple children
 // Implements the Child Interface.
 func (he *UIObject) AddChildren(ui []HTMLElementWriter) HTMLElementWriter {
@@ -401,29 +647,13 @@ func (he *UIObject) AddChildren(ui []HTMLElementWriter) HTMLElementWriter {
 // For example, SetOrder("itemX", "item2") will set ItemX before Item2
 // Implements the Child Interface.
 func (he *UIObject) SetChildOrder(thisId, beforeId string) HTMLElementWriter {
-	var obj, mark *list.Element
-	x := he.childOrder.Front()
-	for ; x != nil || (obj != nil && mark != nil); x = x.Next() {
-		if x.Value.(HTMLElementWriter).Id() == thisId {
-			obj = x
-		}
-		if x.Value.(HTMLElementWriter).Id() == beforeId {
-			mark = x
-		}
-	}
-	if obj != nil && mark != nil {
-		he.childOrder.Remove(x)
-		he.childOrder.InsertBefore(x.Value, mark)
+	obj, mark := he.childElement(thisId), he.childElement(beforeId)
+	if obj != nil && mark != nil && obj != mark {
+		he.childOrder.MoveBefore(obj, mark)
 	}
 	return he
 }
 
-// Generate a unique Id based on a prefix, affixing a random number.
-// The max parameter determines the bounded range [0, max)
-func generateId(prefix string, max int) string {
-	return fmt.Sprintf("%s#%d", prefix, rand.Intn(max))
-}
-
 func StripWhitespace(s string) string {
 	return strings.Trim(s, "\n\t")
 }
\ No newline at end of file
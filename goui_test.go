@@ -5,6 +5,7 @@ import (
 	"github.com/mooredwightd/gotestutil"
 	"reflect"
 	"strings"
+	"encoding/json"
 )
 
 var (
@@ -31,8 +32,56 @@ func TestNewElement(t *testing.T) {
 		"text field does not match. Actual: %s", testElement.text)
 }
 
+const testMenuJSON = `{"id":"nav", "type":"menu", "children":[
+	{"id":"m1", "type":"link", "text":"One", "attributes":{"href":"/one"}},
+	{"id":"m2", "type":"menu", "children":[
+		{"id":"m2a", "type":"link", "text":"Two A", "children":[{"id":"m2a1", "type":"span"}]},
+		{"id":"m2b", "type":"link", "text":"Two B"}]},
+	{"id":"m3", "type":"separator"}]}`
+
 func TestNewElementFromJSON(t *testing.T) {
-	t.Skipped()
+	t.Run("A1", func(t *testing.T) {
+		uio, err := NewElementFromJSON(testMenuJSON)
+		if err != nil {
+			t.Fatalf("Error on NewElementFromJSON: %s.\n", err)
+		}
+		gotestutil.AssertEqual(t, uio.ChildCount(), 3, "Expected three (3) children. Actual: %d.", uio.ChildCount())
+		m1 := uio.GetChildById("m1")
+		gotestutil.AssertNotNil(t, m1, "Expected valid element for id \"m1\".")
+		gotestutil.AssertStringsEqual(t, m1.GetAttribute("href"), "/one",
+			"Expected href \"/one\". Actual: %s.", m1.GetAttribute("href"))
+		x := uio.SearchChildrenById("m2a1")
+		gotestutil.AssertNotNil(t, x, "Expected valid element for grandchild id \"m2a1\".")
+	})
+
+	t.Run("B1", func(t *testing.T) {
+		_, err := NewElementFromJSON(`{"id":`)
+		gotestutil.AssertNotNil(t, err, "Expected error for invalid JSON.")
+	})
+}
+
+func TestUIObject_MarshalJSON(t *testing.T) {
+	uio, err := NewElementFromJSON(testMenuJSON)
+	if err != nil {
+		t.Fatalf("Error on NewElementFromJSON: %s.\n", err)
+	}
+	b, err := json.Marshal(uio)
+	if err != nil {
+		t.Fatalf("Error on MarshalJSON: %s.\n", err)
+	}
+
+	var x UIObject
+	if err = json.Unmarshal(b, &x); err != nil {
+		t.Fatalf("Error on UnmarshalJSON: %s.\n", err)
+	}
+	chList := x.ChildrenByOrder()
+	gotestutil.AssertEqual(t, len(chList), 3, "Expected three (3) children. Actual: %d.", len(chList))
+	gotestutil.AssertStringsEqual(t, chList[0].Id(), "m1", "Expected id \"m1\". Actual: %s.", chList[0].Id())
+	gotestutil.AssertStringsEqual(t, chList[2].Id(), "m3", "Expected id \"m3\". Actual: %s.", chList[2].Id())
+	gotestutil.AssertNotNil(t, x.SearchChildrenById("m2a1"), "Expected valid element for grandchild id \"m2a1\".")
+
+	b2, _ := json.Marshal(&x)
+	gotestutil.AssertStringsEqual(t, string(b2), string(b), "Expected identical JSON after round trip.")
 }
 
 type testInterfaceObj struct {
@@ -235,4 +284,43 @@ func TestUIObject_ChildrenByOrder(t *testing.T) {
 	gotestutil.AssertStringsEqual(t, chList[0].Id(), "ch1", "Expected id \"ch1\". Actual: %s.", chList[0].Id())
 	gotestutil.AssertStringsEqual(t, chList[1].Id(), "ch2", "Expected id \"ch2\". Actual: %s.", chList[1].Id())
 	gotestutil.AssertStringsEqual(t, chList[2].Id(), "ch3", "Expected id \"ch3\". Actual: %s.", chList[2].Id())
-}
\ No newline at end of file
+}
+func TestUIObject_AppendChild(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		_, err := NewElementFromJSON(`{"id":"p", "children":[{"id":"a"}, {"id":"a"}]}`)
+		gotestutil.AssertNotNil(t, err, "Expected error for duplicate ids in JSON.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		p := NewElement("div", "p", "", "")
+		p.AddChild(NewElement("span", "a", "", "first"))
+		p.AddChild(NewElement("span", "a", "", "second"))
+		gotestutil.AssertEqual(t, len(p.ChildrenByOrder()), 1, "Expected one (1) child.")
+		gotestutil.AssertStringsEqual(t, p.GetChildById("a").Text(), "first", "Expected the first child kept.")
+	})
+}
+
+func TestUIObject_ClassTokens(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		uio := NewElement("button", "b1", "btn-primary  btn-primary", "")
+		gotestutil.AssertStringsEqual(t, uio.Class(), "btn-primary", "Expected duplicates removed. Actual: %s.", uio.Class())
+		uio.AddCssClass("btn")
+		gotestutil.AssertStringsEqual(t, uio.Class(), "btn-primary btn", "Expected \"btn\" added. Actual: %s.", uio.Class())
+		uio.RemoveCssClass("btn")
+		gotestutil.AssertStringsEqual(t, uio.Class(), "btn-primary", "Expected \"btn-primary\" kept. Actual: %s.",
+			uio.Class())
+	})
+
+	t.Run("A2", func(t *testing.T) {
+		uio := NewElement("button", "b1", "btn btn-lg", "")
+		gotestutil.AssertTrue(t, uio.HasClass("btn"), "Expected class \"btn\".")
+		gotestutil.AssertFalse(t, uio.HasClass("bt"), "Expected no class \"bt\".")
+		uio.ToggleClass("active").ToggleClass("btn")
+		gotestutil.AssertEqual(t, uio.ClassList(), []string{"btn-lg", "active"}, "Unexpected classes. Actual: %v.",
+			uio.ClassList())
+		uio.ReplaceClass("btn-lg", "btn-sm").ReplaceClass("dummy", "x")
+		gotestutil.AssertEqual(t, uio.ClassList(), []string{"btn-sm", "active"}, "Unexpected classes. Actual: %v.",
+			uio.ClassList())
+		uio.SetClasses("a b", "c", "a")
+		gotestutil.AssertStringsEqual(t, uio.Class(), "a b c", "Unexpected classes. Actual: %s.", uio.Class())
+	})
+}
@@ -0,0 +1,74 @@
+package goui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Generation of ids for elements added without one.
+//
+
+// Creates an id for a child element that is added to a parent without an id.
+// The id must be unique among the children of the parent. Set the generator used by AddChild
+// with UIContext.SetIdGenerator, for elements created by UIContext.NewElement.
+type IdGenerator interface {
+	GenerateId(parent, child HTMLElementWriter) string
+}
+
+// Adapts an ordinary function to the IdGenerator interface.
+type IdGeneratorFunc func(parent, child HTMLElementWriter) string
+
+func (f IdGeneratorFunc) GenerateId(parent, child HTMLElementWriter) string {
+	return f(parent, child)
+}
+
+// The default IdGenerator. Ids are the parent id, the child content type, and a per-parent counter,
+// e.g. "nav-link-3" for the third child of "nav". The same tree built in the same order always has
+// the same ids, and the ids can be used in a CSS selector without escaping.
+type PathIdGenerator struct{}
+
+func (PathIdGenerator) GenerateId(parent, child HTMLElementWriter) string {
+	prefix := SelectorSafeId(child.ContentType())
+	if len(prefix) == 0 {
+		prefix = "el"
+	}
+	if pid := SelectorSafeId(parent.Id()); len(pid) > 0 {
+		prefix = pid + "-" + prefix
+	}
+	for n := parent.ChildCount() + 1; ; n++ {
+		id := prefix + "-" + strconv.Itoa(n)
+		if parent.GetChildById(id) == nil {
+			return id
+		}
+	}
+}
+
+// Convert a string into an id that can be used in a CSS selector without escaping.
+// Characters other than letters, digits, "-" and "_" are replaced by "-", and an id that doesn't
+// start with a letter is prefixed with "id-". The empty string is unchanged.
+func SelectorSafeId(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	x := strings.Map(func(r rune) rune {
+		if r < 0x80 && isNameChar(byte(r)) {
+			return r
+		}
+		return '-'
+	}, s)
+	if c := x[0]; !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') {
+		x = "id-" + x
+	}
+	return x
+}
+
+// The IdGenerator for the children of the element: the generator of the context that created the
+// element or its nearest ancestor, or the default.
+func (he *UIObject) idGenerator() IdGenerator {
+	for p := he; p != nil; p = p.parent {
+		if p.idGen != nil {
+			return p.idGen
+		}
+	}
+	return PathIdGenerator{}
+}
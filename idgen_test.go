@@ -0,0 +1,63 @@
+package goui
+
+import (
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestPathIdGenerator_GenerateId(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		m := NewElement(ContentTypeMenu, "nav", "", "")
+		m.AddChild(NewElement(ContentTypeLink, "", "", "One"))
+		m.AddChild(NewElement(ContentTypeLink, "", "", "Two"))
+		m.AddChild(NewElement("", "", "", "Three"))
+		x := childIds(m)
+		gotestutil.AssertEqual(t, x, []string{"nav-link-1", "nav-link-2", "nav-el-3"}, "Unexpected ids. Actual: %v.", x)
+	})
+
+	t.Run("A2", func(t *testing.T) {
+		m := NewElement(ContentTypeMenu, "", "", "")
+		m.AddChild(NewElement(ContentTypeLink, "link-2", "", ""))
+		m.AddChild(NewElement(ContentTypeLink, "", "", ""))
+		x := childIds(m)
+		gotestutil.AssertEqual(t, x, []string{"link-2", "link-3"}, "Unexpected ids. Actual: %v.", x)
+	})
+}
+
+func TestSelectorSafeId(t *testing.T) {
+	for k, v := range map[string]string{"": "", "link": "link", "link#42": "link-42", "9a": "id-9a", "a b.c": "a-b-c"} {
+		x := SelectorSafeId(k)
+		gotestutil.AssertStringsEqual(t, x, v, "Unexpected id for %q. Actual: %s.", k, x)
+	}
+}
+
+func TestUIContext_SetIdGenerator(t *testing.T) {
+	uic := NewUIContext()
+	uic.SetIdGenerator(IdGeneratorFunc(func(parent, child HTMLElementWriter) string {
+		return "fixed"
+	}))
+	other := NewElement(ContentTypeMenu, "other", "", "")
+	other.AddChild(NewElement(ContentTypeLink, "", "", "One"))
+	gotestutil.AssertNotNil(t, other.GetChildById("other-link-1"), "Expected the default generator for other elements.")
+	gotestutil.AssertNotNil(t, NewUIContext().NewElement("div", "x", "", "").
+		AddChild(NewElement("p", "", "", "")).GetChildById("x-p-1"), "Expected the default generator in a new context.")
+
+	m := uic.NewElement(ContentTypeMenu, "nav", "", "")
+	err := m.AppendChild(NewElement(ContentTypeLink, "", "", "One"))
+	gotestutil.AssertNil(t, err, "Expected nil error on AppendChild.")
+	err = m.AppendChild(NewElement(ContentTypeLink, "", "", "Two"))
+	gotestutil.AssertNotNil(t, err, "Expected duplicate id error on AppendChild.")
+	gotestutil.AssertTrue(t, err.(*Error).Err == ErrDuplicateId, "Expected ErrDuplicateId. Actual: %s.", err)
+	gotestutil.AssertEqual(t, m.ChildCount(), 1, "Expected one (1) child. Actual: %d.", m.ChildCount())
+	gotestutil.AssertStringsEqual(t, m.GetChildById("fixed").Text(), "One", "Expected the first child kept.")
+
+	s := uic.NewSelect("size").Option("s", "Small")
+	gotestutil.AssertNotNil(t, s.GetChildById("fixed"), "Expected the context generator for a select.")
+	dl := uic.NewDatalist("sizes", "Small")
+	gotestutil.AssertNotNil(t, dl.GetChildById("fixed"), "Expected the context generator for a datalist.")
+	f := uic.NewElement(ContentTypeForm, "f", "", "")
+	f.AddChild(NewSelect("color"))
+	toUIObject(f.GetChildById("color")).AddChild(NewOption("r", "Red"))
+	gotestutil.AssertNotNil(t, f.SearchChildrenById("fixed"), "Expected the generator of the context element inherited.")
+}
@@ -0,0 +1,162 @@
+package goui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Images with responsive sources, lazy loading and placeholders. An image is a ContentTypeImage
+// element, and its text is the alt text, so it can be a child of any element, e.g. a menu link.
+//
+// Example:
+//     img, err := NewImage("/img/cat-640.jpg", "A cat asleep on a keyboard")
+//     img.Size(640, 480).Lazy(true).Sizes("(max-width: 600px) 100vw", "50vw").
+//         SrcSet(func(w int) string { return fmt.Sprintf("/img/cat-%d.jpg", w) }, 320, 640, 1280)
+//     pic := NewPicture(img).Source("image/webp", SrcSet(webpURL, 320, 640, 1280))
+
+// Returned by NewImage for an image without alt text.
+var ErrImageAlt = errors.New("missing image alt text")
+
+// Percent-encodes the characters of a URL that would end a CSS string, or that a style value can't
+// contain.
+var cssURLEscaper = strings.NewReplacer("<", "%3C", ">", "%3E", `"`, "%22", `\`, "%5C", "\n", "%0A", "\r", "%0D")
+
+// An <img> element.
+type ImageElement struct {
+	*UIObject
+}
+
+// Create an image. The alt text describes the image for screen readers, and is shown if the image
+// can't be loaded. Returns ErrImageAlt if the alt text is empty. For an image that is only
+// decoration, use NewDecorativeImage.
+func NewImage(src, alt string) (*ImageElement, error) {
+	if len(strings.TrimSpace(alt)) == 0 {
+		return nil, errorf(fmt.Sprintf("Image %q", src), ErrImageAlt)
+	}
+	img := &ImageElement{NewElement(ContentTypeImage, "", "", alt)}
+	img.attrs["src"] = src
+	return img, nil
+}
+
+// Create an image that is only decoration, with an empty alt text, so screen readers skip it.
+func NewDecorativeImage(src string) *ImageElement {
+	img := &ImageElement{NewElement(ContentTypeImage, "", "", "")}
+	img.attrs["src"] = src
+	img.attrs["alt"] = ""
+	return img
+}
+
+// Use an existing element, e.g. one read with NewElementFromJSON, as an image.
+func AsImage(ui HTMLElementWriter) *ImageElement {
+	return &ImageElement{toUIObject(ui)}
+}
+
+// Set the width and height, in pixels, so the browser reserves the space before the image loads.
+func (img *ImageElement) Size(width, height int) *ImageElement {
+	img.attrs["width"] = strconv.Itoa(width)
+	img.attrs["height"] = strconv.Itoa(height)
+	return img
+}
+
+// Set or clear lazy loading. A lazy image is loaded when it is near the viewport, and decoded
+// without blocking the page.
+func (img *ImageElement) Lazy(on bool) *ImageElement {
+	if on {
+		img.attrs["loading"] = "lazy"
+		img.attrs["decoding"] = "async"
+	} else {
+		delete(img.attrs, "loading")
+		delete(img.attrs, "decoding")
+	}
+	return img
+}
+
+// Set the srcset attribute from the URLs of the image at each width. See SrcSet.
+func (img *ImageElement) SrcSet(url func(width int) string, widths ...int) *ImageElement {
+	if s := SrcSet(url, widths...); len(s) > 0 {
+		img.attrs["srcset"] = s
+	}
+	return img
+}
+
+// Set the sizes attribute, the width of the image for media conditions, e.g.
+// Sizes("(max-width: 600px) 100vw", "50vw"). The last size has no condition.
+func (img *ImageElement) Sizes(sizes ...string) *ImageElement {
+	img.attrs["sizes"] = strings.Join(sizes, ", ")
+	return img
+}
+
+// Show a placeholder, e.g. a small blurred image or a data: URL, until the image loads. The
+// placeholder is the CSS background of the image. Characters that can't be in a CSS url(), e.g.
+// the "<" of an SVG data: URL, are percent-encoded, and so is "#" in a data: URL, e.g. in a color.
+func (img *ImageElement) Placeholder(src string) *ImageElement {
+	src = cssURLEscaper.Replace(src)
+	if strings.HasPrefix(src, "data:") {
+		src = strings.Replace(src, "#", "%23", -1)
+	}
+	img.SetStyle("background-image", `url("`+src+`")`)
+	img.SetStyle("background-size", "cover")
+	return img
+}
+
+// Return a srcset value, e.g. "/a-320.jpg 320w, /a-640.jpg 640w", with the URL of the image at each
+// width. Widths less than 1 are logged and skipped.
+func SrcSet(url func(width int) string, widths ...int) string {
+	x := make([]string, 0, len(widths))
+	for _, w := range widths {
+		if w < 1 {
+			log.Printf("goui.SrcSet: invalid width %d", w)
+			continue
+		}
+		x = append(x, url(w)+" "+strconv.Itoa(w)+"w")
+	}
+	return strings.Join(x, ", ")
+}
+
+// A <picture> element, with <source> elements for alternate formats and the <img>, which is used
+// if the browser supports none of the sources.
+type PictureElement struct {
+	*UIObject
+}
+
+// Create a picture with the image. If the image has an id, the picture id is the image id with the
+// suffix "-picture".
+func NewPicture(img *ImageElement) *PictureElement {
+	var id string
+	if len(img.id) > 0 {
+		id = SelectorSafeId(img.id + "-picture")
+	}
+	p := &PictureElement{NewElement(ContentTypePicture, id, "", "")}
+	p.AddChild(img)
+	return p
+}
+
+// Add a source for a MIME type, e.g. "image/webp", with a srcset value, e.g. from SrcSet. The
+// source has the sizes of the image. Sources are used in the order they are added.
+func (p *PictureElement) Source(mimeType, srcset string) *PictureElement {
+	s := NewElement(ContentTypeSource, "", "", "")
+	s.attrs["type"] = mimeType
+	s.attrs["srcset"] = srcset
+	if img := p.Image(); img != nil {
+		if sizes, ok := img.attrs["sizes"]; ok {
+			s.attrs["sizes"] = sizes
+		}
+	}
+	if err := p.InsertChildAt(p.ChildCount()-1, s); err != nil {
+		log.Printf("goui.Source: %s", err)
+	}
+	return p
+}
+
+// Return the image of the picture, or nil.
+func (p *PictureElement) Image() *ImageElement {
+	for _, c := range p.ChildrenByOrder() {
+		if uio := toUIObject(c); uio.contentType == ContentTypeImage {
+			return &ImageElement{uio}
+		}
+	}
+	return nil
+}
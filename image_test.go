@@ -0,0 +1,74 @@
+package goui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func catURL(w int) string {
+	return fmt.Sprintf("/img/cat-%d.jpg", w)
+}
+
+func TestNewImage(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		img, err := NewImage("/img/cat.jpg", "A cat")
+		gotestutil.AssertNil(t, err, "Unexpected error on NewImage. %v", err)
+		img.Size(640, 480).Lazy(true).Sizes("(max-width: 600px) 100vw", "50vw").SrcSet(catURL, 320, 0, 640)
+		x := renderString(t, img.UIObject)
+		gotestutil.AssertStringsEqual(t, x, `<img alt="A cat" decoding="async" height="480" loading="lazy" `+
+			`sizes="(max-width: 600px) 100vw, 50vw" src="/img/cat.jpg" `+
+			`srcset="/img/cat-320.jpg 320w, /img/cat-640.jpg 640w" width="640">`,
+			"Unexpected image HTML. Actual: %s.", x)
+
+		img.Lazy(false).Placeholder("data:image/gif;base64,R0lGOD")
+		gotestutil.AssertEmptyString(t, img.GetAttribute("loading"), "Expected lazy loading cleared.")
+		gotestutil.AssertStringsEqual(t, img.Style("background-image"), `url("data:image/gif;base64,R0lGOD")`,
+			"Expected the placeholder background.")
+
+		svg := `data:image/svg+xml;utf8,<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 4 3">` +
+			`<rect width="4" height="3" fill="#ccc"/></svg>`
+		img.Placeholder(svg)
+		gotestutil.AssertStringsEqual(t, img.Style("background-image"),
+			`url("data:image/svg+xml;utf8,%3Csvg xmlns=%22http://www.w3.org/2000/svg%22 viewBox=%220 0 4 3%22%3E`+
+				`%3Crect width=%224%22 height=%223%22 fill=%22%23ccc%22/%3E%3C/svg%3E")`,
+			"Expected the SVG placeholder encoded.")
+
+		menu := NewMenu("nav")
+		link := NewElement(ContentTypeLink, "home", "", "")
+		link.AddChild(img)
+		menu.AddChild(link)
+		gotestutil.AssertNotNil(t, menu.QueryOne("a img"), "Expected an image in a menu link.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		img, err := NewImage("/img/cat.jpg", "  ")
+		gotestutil.AssertNil(t, img, "Expected no image without alt text.")
+		gotestutil.AssertTrue(t, err != nil && err.(*Error).Err == ErrImageAlt, "Expected ErrImageAlt.")
+
+		x := renderString(t, NewDecorativeImage("/img/line.png").UIObject)
+		gotestutil.AssertStringsEqual(t, x, `<img alt="" src="/img/line.png">`, "Unexpected decorative image. Actual: %s.", x)
+	})
+}
+
+func TestNewPicture(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		img, _ := NewImage("/img/cat.jpg", "A cat")
+		img.SetId("cat")
+		img.Sizes("50vw")
+		p := NewPicture(img).Source("image/avif", "/img/cat.avif").
+			Source("image/webp", SrcSet(func(w int) string { return fmt.Sprintf("/img/cat-%d.webp", w) }, 320))
+		x := renderString(t, p.UIObject)
+		gotestutil.AssertStringsEqual(t, x, `<picture id="cat-picture">`+
+			`<source id="cat-picture-source-2" sizes="50vw" srcset="/img/cat.avif" type="image/avif">`+
+			`<source id="cat-picture-source-3" sizes="50vw" srcset="/img/cat-320.webp 320w" type="image/webp">`+
+			`<img id="cat" alt="A cat" sizes="50vw" src="/img/cat.jpg"></picture>`,
+			"Unexpected picture HTML. Actual: %s.", x)
+	})
+	t.Run("B1", func(t *testing.T) {
+		img := NewDecorativeImage("/img/line.png")
+		p := NewPicture(img)
+		gotestutil.AssertEmptyString(t, p.Id(), "Expected no picture id for an image without an id.")
+		gotestutil.AssertTrue(t, p.Image().UIObject == img.UIObject, "Expected the picture image.")
+	})
+}
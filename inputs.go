@@ -0,0 +1,381 @@
+package goui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Typed builders for <input> elements. Each builder embeds a *UIObject, so the result can be
+// added to any element with AddChild and rendered or used in templates as usual.
+//
+// Example:
+//     form.AddChild(NewNumberInput("qty").Min(0).Max(10).Step(0.5).Label("Quantity"))
+
+// Attributes allowed on every element.
+var globalAttributes = map[string]bool{
+	"accesskey": true, "autocapitalize": true, "autofocus": true, "class": true, "contenteditable": true,
+	"dir": true, "draggable": true, "enterkeyhint": true, "hidden": true, "id": true, "inert": true,
+	"inputmode": true, "is": true, "lang": true, "nonce": true, "popover": true, "role": true,
+	"spellcheck": true, "style": true, "tabindex": true, "title": true, "translate": true,
+}
+
+// Attributes allowed on every input type.
+var commonInputAttributes = []string{"disabled", "form", "name", "value"}
+
+// Input type specific attributes, from the HTML specification. The key is the attribute, and the
+// value lists the content types that allow it.
+var inputAttributes = map[string][]string{
+	"accept": {ContentInputFile},
+	"alt":    {ContentInputImage},
+	"autocomplete": append(textInputTypes(), append(dateInputTypes(), ContentInputHidden, ContentInputPassword,
+		ContentInputNumber, ContentInputRange, ContentInputColor)...),
+	"capture":        {ContentInputFile},
+	"checked":        {ContentInputCheckbox, ContentInputRadio},
+	"dirname":        {ContentInputText, ContentInputSearch},
+	"formaction":     {ContentInputSubmit, ContentInputImage},
+	"formenctype":    {ContentInputSubmit, ContentInputImage},
+	"formmethod":     {ContentInputSubmit, ContentInputImage},
+	"formnovalidate": {ContentInputSubmit, ContentInputImage},
+	"formtarget":     {ContentInputSubmit, ContentInputImage},
+	"height":         {ContentInputImage},
+	"list": append(textInputTypes(), append(dateInputTypes(), ContentInputNumber, ContentInputRange,
+		ContentInputColor)...),
+	"max":           append(dateInputTypes(), ContentInputNumber, ContentInputRange),
+	"maxlength":     append(textInputTypes(), ContentInputPassword),
+	"min":           append(dateInputTypes(), ContentInputNumber, ContentInputRange),
+	"minlength":     append(textInputTypes(), ContentInputPassword),
+	"multiple":      {ContentInputEmail, ContentInputFile},
+	"pattern":       append(textInputTypes(), ContentInputPassword),
+	"placeholder":   append(textInputTypes(), ContentInputPassword, ContentInputNumber),
+	"popovertarget": {ContentInputButton, ContentInputReset, ContentInputSubmit, ContentInputImage},
+	"readonly": append(textInputTypes(), append(dateInputTypes(), ContentInputPassword,
+		ContentInputNumber)...),
+	"required": append(textInputTypes(), append(dateInputTypes(), ContentInputPassword, ContentInputNumber,
+		ContentInputCheckbox, ContentInputRadio, ContentInputFile)...),
+	"size":  append(textInputTypes(), ContentInputPassword),
+	"src":   {ContentInputImage},
+	"step":  append(dateInputTypes(), ContentInputNumber, ContentInputRange),
+	"width": {ContentInputImage},
+}
+
+func textInputTypes() []string {
+	return []string{ContentInputText, ContentInputSearch, ContentInputUrl, ContentInputTel, ContentInputEmail}
+}
+
+func dateInputTypes() []string {
+	return []string{ContentInputDate, ContentInputMonth, ContentInputWeek, ContentInputTime, ContentInputDateTimeLoc}
+}
+
+// Reports whether an attribute is valid on an input of the content type, e.g. "max" is allowed on
+// ContentInputNumber but not ContentInputText. Global, data-*, aria-* and event handler attributes
+// are allowed on every type.
+func InputAttributeAllowed(contentType, name string) bool {
+	name = strings.ToLower(name)
+	if globalAttributes[name] || containsString(commonInputAttributes, name) ||
+		strings.HasPrefix(name, dataPrefix) || strings.HasPrefix(name, ariaPrefix) || strings.HasPrefix(name, "on") {
+		return true
+	}
+	return containsString(inputAttributes[name], contentType)
+}
+
+// Reports whether a form control of the content type displays its text in a <label>: inputs,
+// selects and textareas. Buttons show the text as their value, and hidden and image inputs have no label.
+func inputHasLabel(contentType string) bool {
+	switch contentType {
+	case ContentInputButton, ContentInputSubmit, ContentInputReset, ContentInputHidden, ContentInputImage:
+		return false
+	}
+	if _, ok := contentTags[contentType]; !ok {
+		return false
+	}
+	switch tagForContentType(contentType).name {
+	case "input", "select", "textarea":
+		return true
+	}
+	return false
+}
+
+// An <input> element of one of the ContentInput* types. The text of the element is rendered as a
+// <label for=...> paired with the input.
+type InputElement struct {
+	*UIObject
+}
+
+// Reports whether the content type is one of the ContentInput* types.
+func isInputType(contentType string) bool {
+	return len(contentTags[contentType].inputType) > 0
+}
+
+// Create an input of one of the ContentInput* types. The name is the form field name, and the id
+// is derived from it, so a label can refer to the input.
+func NewInput(contentType, name string) *InputElement {
+	return GetUIConfig().NewInput(contentType, name)
+}
+
+// Create an input, as NewInput, with the context IdGenerator.
+func (uic *UIContext) NewInput(contentType, name string) *InputElement {
+	in := &InputElement{uic.NewElement(contentType, SelectorSafeId(name), "", "")}
+	if len(name) > 0 {
+		in.attrs["name"] = name
+	}
+	return in
+}
+
+// Add a boolean attribute, if it is valid for the input type.
+func (in *InputElement) setBool(attName string, on bool) {
+	if on {
+		in.AddAttribute(attName, "")
+	} else {
+		in.RemoveAttribute(attName)
+	}
+}
+
+// Set the text of the <label> paired with the input.
+func (in *InputElement) Label(text string) *InputElement {
+	in.SetText(text)
+	return in
+}
+
+// Set the value attribute.
+func (in *InputElement) Value(v string) *InputElement {
+	in.AddAttribute("value", v)
+	return in
+}
+
+// Set or clear the required attribute.
+func (in *InputElement) Required(on bool) *InputElement {
+	in.setBool("required", on)
+	return in
+}
+
+// Set or clear the disabled attribute.
+func (in *InputElement) Disabled(on bool) *InputElement {
+	in.setBool("disabled", on)
+	return in
+}
+
+// Set the placeholder attribute. Refused for types without a placeholder.
+func (in *InputElement) Placeholder(s string) *InputElement {
+	in.AddAttribute("placeholder", s)
+	return in
+}
+
+// Text inputs: text, search, url, tel, email and password.
+type TextInput struct {
+	*InputElement
+}
+
+func newTextInput(contentType, name string) *TextInput {
+	return &TextInput{NewInput(contentType, name)}
+}
+
+func NewTextInput(name string) *TextInput     { return newTextInput(ContentInputText, name) }
+func NewSearchInput(name string) *TextInput   { return newTextInput(ContentInputSearch, name) }
+func NewUrlInput(name string) *TextInput      { return newTextInput(ContentInputUrl, name) }
+func NewTelInput(name string) *TextInput      { return newTextInput(ContentInputTel, name) }
+func NewEmailInput(name string) *TextInput    { return newTextInput(ContentInputEmail, name) }
+func NewPasswordInput(name string) *TextInput { return newTextInput(ContentInputPassword, name) }
+
+// Set the minimum number of characters.
+func (in *TextInput) MinLength(n int) *TextInput {
+	in.AddAttribute("minlength", strconv.Itoa(n))
+	return in
+}
+
+// Set the maximum number of characters.
+func (in *TextInput) MaxLength(n int) *TextInput {
+	in.AddAttribute("maxlength", strconv.Itoa(n))
+	return in
+}
+
+// Set the regular expression the value must match.
+func (in *TextInput) Pattern(re string) *TextInput {
+	in.AddAttribute("pattern", re)
+	return in
+}
+
+// Allow several comma separated addresses. Refused for types other than email.
+func (in *TextInput) Multiple() *TextInput {
+	in.setBool("multiple", true)
+	return in
+}
+
+// Number and range inputs.
+type NumberInput struct {
+	*InputElement
+}
+
+func NewNumberInput(name string) *NumberInput {
+	return &NumberInput{NewInput(ContentInputNumber, name)}
+}
+
+func NewRangeInput(name string) *NumberInput {
+	return &NumberInput{NewInput(ContentInputRange, name)}
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Set the minimum value.
+func (in *NumberInput) Min(v float64) *NumberInput {
+	in.AddAttribute("min", formatNumber(v))
+	return in
+}
+
+// Set the maximum value.
+func (in *NumberInput) Max(v float64) *NumberInput {
+	in.AddAttribute("max", formatNumber(v))
+	return in
+}
+
+// Set the step between valid values. Use 0 for the "any" step.
+func (in *NumberInput) Step(v float64) *NumberInput {
+	if v == 0 {
+		in.AddAttribute("step", "any")
+	} else {
+		in.AddAttribute("step", formatNumber(v))
+	}
+	return in
+}
+
+// Date and time inputs: date, month, week, time and datetime-local.
+type DateInput struct {
+	*InputElement
+}
+
+func newDateInput(contentType, name string) *DateInput {
+	return &DateInput{NewInput(contentType, name)}
+}
+
+func NewDateInput(name string) *DateInput        { return newDateInput(ContentInputDate, name) }
+func NewMonthInput(name string) *DateInput       { return newDateInput(ContentInputMonth, name) }
+func NewWeekInput(name string) *DateInput        { return newDateInput(ContentInputWeek, name) }
+func NewTimeInput(name string) *DateInput        { return newDateInput(ContentInputTime, name) }
+func NewDateTimeLocInput(name string) *DateInput { return newDateInput(ContentInputDateTimeLoc, name) }
+
+// Format a time in the value format of the input type, e.g. "2006-01-02" for a date input.
+func FormatInputTime(contentType string, t time.Time) string {
+	switch contentType {
+	case ContentInputMonth:
+		return t.Format("2006-01")
+	case ContentInputWeek:
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", y, w)
+	case ContentInputTime:
+		return t.Format("15:04")
+	case ContentInputDateTimeLoc:
+		return t.Format("2006-01-02T15:04")
+	}
+	return t.Format("2006-01-02")
+}
+
+// Set the earliest and latest valid times. A zero time leaves that end open.
+func (in *DateInput) Range(from, to time.Time) *DateInput {
+	if !from.IsZero() {
+		in.AddAttribute("min", FormatInputTime(in.contentType, from))
+	}
+	if !to.IsZero() {
+		in.AddAttribute("max", FormatInputTime(in.contentType, to))
+	}
+	return in
+}
+
+// Set the value from a time.
+func (in *DateInput) Time(t time.Time) *DateInput {
+	in.AddAttribute("value", FormatInputTime(in.contentType, t))
+	return in
+}
+
+// Set the step, in the units of the input type: days, months, weeks or seconds.
+func (in *DateInput) Step(n int) *DateInput {
+	in.AddAttribute("step", strconv.Itoa(n))
+	return in
+}
+
+// Checkbox and radio inputs. Inputs in a group share the name, so the id also includes the value.
+type CheckInput struct {
+	*InputElement
+}
+
+func NewCheckboxInput(name, value string) *CheckInput {
+	in := &CheckInput{NewInput(ContentInputCheckbox, name)}
+	if len(value) > 0 {
+		in.SetId(SelectorSafeId(name + "-" + value))
+	}
+	in.Value(value)
+	return in
+}
+
+func NewRadioInput(name, value string) *CheckInput {
+	in := &CheckInput{NewInput(ContentInputRadio, name)}
+	in.SetId(SelectorSafeId(name + "-" + value))
+	in.Value(value)
+	return in
+}
+
+// Set or clear the checked attribute.
+func (in *CheckInput) Checked(on bool) *CheckInput {
+	in.setBool("checked", on)
+	return in
+}
+
+// File inputs.
+type FileInput struct {
+	*InputElement
+}
+
+func NewFileInput(name string) *FileInput {
+	return &FileInput{NewInput(ContentInputFile, name)}
+}
+
+// Set the accepted file types, e.g. "image/*" or ".pdf,.doc".
+func (in *FileInput) Accept(types ...string) *FileInput {
+	in.AddAttribute("accept", strings.Join(types, ","))
+	return in
+}
+
+// Allow more than one file.
+func (in *FileInput) Multiple() *FileInput {
+	in.setBool("multiple", true)
+	return in
+}
+
+// Request a camera capture, "user" or "environment".
+func (in *FileInput) Capture(facing string) *FileInput {
+	in.AddAttribute("capture", facing)
+	return in
+}
+
+// Buttons: button, submit and reset. The text is the button caption.
+func NewButtonInput(name, text string) *InputElement {
+	return NewInput(ContentInputButton, name).Label(text)
+}
+
+func NewSubmitInput(name, text string) *InputElement {
+	return NewInput(ContentInputSubmit, name).Label(text)
+}
+
+func NewResetInput(name, text string) *InputElement {
+	return NewInput(ContentInputReset, name).Label(text)
+}
+
+// A hidden input with a value.
+func NewHiddenInput(name, value string) *InputElement {
+	return NewInput(ContentInputHidden, name).Value(value)
+}
+
+// A color input.
+func NewColorInput(name string) *InputElement {
+	return NewInput(ContentInputColor, name)
+}
+
+// An image submit button.
+func NewImageInput(name, src, alt string) *InputElement {
+	in := NewInput(ContentInputImage, name)
+	in.AddAttribute("src", src)
+	in.AddAttribute("alt", alt)
+	return in
+}
@@ -0,0 +1,98 @@
+package goui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestNewNumberInput(t *testing.T) {
+	in := NewNumberInput("qty").Min(0).Max(10).Step(0.5)
+	in.Label("Quantity")
+	x := renderString(t, in.UIObject)
+	gotestutil.AssertStringsEqual(t, x,
+		`<label for="qty">Quantity</label><input type="number" id="qty" max="10" min="0" name="qty" step="0.5">`,
+		"Unexpected number input HTML. Actual: %s.", x)
+
+	in.AddAttribute("maxlength", "3").AddAttribute("accept", "image/*")
+	gotestutil.AssertEmptyString(t, in.GetAttribute("maxlength"), "Expected maxlength refused for number input.")
+	gotestutil.AssertEmptyString(t, in.GetAttribute("accept"), "Expected accept refused for number input.")
+	in.AddAttribute("data-unit", "kg")
+	gotestutil.AssertStringsEqual(t, in.GetAttribute("data-unit"), "kg", "Expected data-* attribute allowed.")
+}
+
+func TestNewDateInput(t *testing.T) {
+	from := time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2017, 12, 31, 0, 0, 0, 0, time.UTC)
+	in := NewDateInput("start").Range(from, to)
+	gotestutil.AssertStringsEqual(t, in.GetAttribute("min"), "2017-01-02", "Unexpected min date.")
+	gotestutil.AssertStringsEqual(t, in.GetAttribute("max"), "2017-12-31", "Unexpected max date.")
+
+	w := NewWeekInput("wk").Time(from)
+	gotestutil.AssertStringsEqual(t, w.GetAttribute("value"), "2017-W01", "Unexpected week value. Actual: %s.",
+		w.GetAttribute("value"))
+}
+
+func TestNewFileInput(t *testing.T) {
+	in := NewFileInput("photo").Accept("image/*").Multiple()
+	in.Required(true)
+	x := renderString(t, in.UIObject)
+	gotestutil.AssertStringsEqual(t, x, `<input type="file" id="photo" accept="image/*" multiple name="photo" required>`,
+		"Unexpected file input HTML. Actual: %s.", x)
+}
+
+func TestNewRadioInput(t *testing.T) {
+	in := NewRadioInput("size", "L").Checked(true)
+	in.Label("Large")
+	x := renderString(t, in.UIObject)
+	gotestutil.AssertStringsEqual(t, x,
+		`<input type="radio" id="size-L" checked name="size" value="L"><label for="size-L">Large</label>`,
+		"Unexpected radio input HTML. Actual: %s.", x)
+
+	f := NewElement("form", "f1", "", "")
+	f.AddChild(in)
+	gotestutil.AssertNotNil(t, f.GetChildById("size-L"), "Expected typed input added as a child.")
+}
+
+func TestNewCheckboxInput(t *testing.T) {
+	f := NewForm("f1", "/", "post")
+	f.AddChild(NewCheckboxInput("tags", "a"))
+	f.AddChild(NewCheckboxInput("tags", "b").Checked(true))
+	gotestutil.AssertEqual(t, f.ChildCount(), 2, "Expected both checkboxes of the group added.")
+	gotestutil.AssertEqual(t, childIds(f.UIObject), []string{"tags-a", "tags-b"}, "Unexpected checkbox ids.")
+	gotestutil.AssertStringsEqual(t, NewCheckboxInput("agree", "").Id(), "agree", "Expected the name as the id.")
+}
+
+func TestInputAttributeAllowed(t *testing.T) {
+	gotestutil.AssertTrue(t, InputAttributeAllowed(ContentInputText, "pattern"), "Expected pattern on text input.")
+	gotestutil.AssertFalse(t, InputAttributeAllowed(ContentInputCheckbox, "pattern"), "Expected no pattern on checkbox.")
+	gotestutil.AssertTrue(t, InputAttributeAllowed(ContentInputCheckbox, "checked"), "Expected checked on checkbox.")
+	gotestutil.AssertTrue(t, InputAttributeAllowed(ContentInputHidden, "aria-hidden"), "Expected aria-* on any input.")
+	for _, ct := range []string{ContentInputPassword, ContentInputNumber, ContentInputRange, ContentInputColor,
+		ContentInputHidden, ContentInputEmail, ContentInputDate} {
+		gotestutil.AssertTrue(t, InputAttributeAllowed(ct, "autocomplete"), "Expected autocomplete on %s.", ct)
+	}
+	for _, ct := range []string{ContentInputCheckbox, ContentInputRadio, ContentInputFile, ContentInputSubmit,
+		ContentInputImage} {
+		gotestutil.AssertFalse(t, InputAttributeAllowed(ct, "autocomplete"), "Expected no autocomplete on %s.", ct)
+	}
+	pw := NewPasswordInput("pw")
+	pw.AddAttribute("autocomplete", "current-password")
+	gotestutil.AssertStringsEqual(t, pw.GetAttribute("autocomplete"), "current-password",
+		"Expected autocomplete on a password input.")
+
+	f := NewForm("f1", "/", "post")
+	f.AddChild(NewNumberInput("qty"))
+	f.GetChildById("qty").AddAttribute("maxlength", "3")
+	gotestutil.AssertEmptyString(t, f.GetChildById("qty").GetAttribute("maxlength"),
+		"Expected maxlength refused on a bare child.")
+	el, _ := NewElementFromJSON(`{"id":"n", "type":"number_input", "attributes":{"min":"1", "pattern":"x"}}`)
+	gotestutil.AssertStringsEqual(t, el.GetAttribute("min"), "1", "Expected min from JSON.")
+	gotestutil.AssertEmptyString(t, el.GetAttribute("pattern"), "Expected pattern from JSON refused.")
+	el = NewElement("div", "d", "", "")
+	el.AddAttribute("pattern", "x").AddAttribute("title", "t")
+	el.SetContentType(ContentInputCheckbox)
+	gotestutil.AssertEmptyString(t, el.GetAttribute("pattern"), "Expected pattern removed on the type change.")
+	gotestutil.AssertStringsEqual(t, el.GetAttribute("title"), "t", "Expected global attribute kept.")
+}
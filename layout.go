@@ -0,0 +1,405 @@
+package goui
+
+import (
+	"log"
+)
+
+// Layout components: grids, cards, tabs, modals, accordions and alerts. Each is an element tree of
+// <div>s, with the classes of the theme of the UIContext that creates it, so it renders with the default renderer, and can
+// be a child of any element, or have any element as a child. Parts have ids derived from the
+// component id, e.g. "<id>-body", for scripts and CSS. Tabs and accordions show and hide parts with
+// the hidden attribute, and modals with the ThemeModalHidden and ThemeModalShown classes of the
+// theme. All have ARIA attributes for the WAI-ARIA patterns; the scripts that make them interactive
+// are up to the page.
+//
+// Example:
+//     card := NewCard("profile", "Profile").Add(form).Footer(NewElement("a", "", "", "Help"))
+//     grid := NewGrid("main")
+//     grid.Row().Col(8, card).Col(4, NewAlert("tip", ThemeAlertInfo, "Changes are saved.").Dismissible())
+
+// Create an element with the classes of the theme roles in the theme. A nil theme is the default.
+func newThemedElement(t Theme, cType, id, text string, roles ...ThemeRole) *UIObject {
+	uio := NewElement(cType, id, "", text)
+	uio.theme = t
+	uio.AddThemeClass(roles...)
+	return uio
+}
+
+// Create a <div> with the classes of the theme roles in the theme.
+func newThemedDiv(t Theme, id string, roles ...ThemeRole) *UIObject {
+	return newThemedElement(t, "div", id, "", roles...)
+}
+
+// Create a close button, for alerts and modals. Scripts find it by the data-dismiss attribute.
+func newDismissButton(t Theme, id, dismiss string) *UIObject {
+	b := newThemedElement(t, "button", id, "×", ThemeAlertDismiss)
+	b.attrs["type"] = "button"
+	b.attrs["data-dismiss"] = dismiss
+	b.SetAria("label", "Close")
+	return b
+}
+
+// A 12 column grid container, with rows of columns.
+type Grid struct {
+	*UIObject
+}
+
+// Create a grid.
+func NewGrid(id string) *Grid {
+	return GetUIConfig().NewGrid(id)
+}
+
+// Create a grid, as NewGrid, with the context theme.
+func (uic *UIContext) NewGrid(id string) *Grid {
+	return &Grid{newThemedDiv(uic.theme, id, ThemeContainer)}
+}
+
+// Add a row, and return it.
+func (g *Grid) Row() *GridRow {
+	r := &GridRow{newThemedDiv(g.currentTheme(), "", ThemeRow)}
+	g.AddChild(r)
+	return r
+}
+
+// A row of a grid.
+type GridRow struct {
+	*UIObject
+}
+
+// Create a row, e.g. for a grid built from a template.
+func NewGridRow(id string) *GridRow {
+	return GetUIConfig().NewGridRow(id)
+}
+
+// Create a row, as NewGridRow, with the context theme.
+func (uic *UIContext) NewGridRow(id string) *GridRow {
+	return &GridRow{newThemedDiv(uic.theme, id, ThemeRow)}
+}
+
+// Add a column that spans a number of the 12 columns of the row, with the children.
+func (r *GridRow) Col(span int, children ...HTMLElementWriter) *GridRow {
+	c := NewElement("div", "", "", "")
+	c.SetClasses(r.currentTheme().Column(span)...)
+	r.AddChild(c)
+	for _, x := range children {
+		c.AddChild(x)
+	}
+	return r
+}
+
+// A card, or panel: an optional header, a body and an optional footer.
+type Card struct {
+	*UIObject
+}
+
+// Create a card. If the title is not empty, the card has a header "<id>-header" with the title.
+// The body is "<id>-body".
+func NewCard(id, title string) *Card {
+	return GetUIConfig().NewCard(id, title)
+}
+
+// Create a card, as NewCard, with the context theme.
+func (uic *UIContext) NewCard(id, title string) *Card {
+	c := &Card{newThemedDiv(uic.theme, id, ThemeCard)}
+	if len(title) > 0 {
+		h := newThemedDiv(uic.theme, SelectorSafeId(id+"-header"), ThemeCardHeader)
+		h.SetText(title)
+		c.AddChild(h)
+	}
+	c.AddChild(newThemedDiv(uic.theme, SelectorSafeId(id+"-body"), ThemeCardBody))
+	return c
+}
+
+// Return the body, or nil if it was removed.
+func (c *Card) Body() *UIObject {
+	if x := c.GetChildById(SelectorSafeId(c.id + "-body")); x != nil {
+		return toUIObject(x)
+	}
+	return nil
+}
+
+// Add children to the body. A card without a body is logged, and is unchanged.
+func (c *Card) Add(children ...HTMLElementWriter) *Card {
+	body := c.Body()
+	if body == nil {
+		log.Printf("goui.Add: card %q has no body", c.id)
+		return c
+	}
+	for _, x := range children {
+		body.AddChild(x)
+	}
+	return c
+}
+
+// Add children to the footer, "<id>-footer". The footer is created by the first call.
+func (c *Card) Footer(children ...HTMLElementWriter) *Card {
+	id := SelectorSafeId(c.id + "-footer")
+	f := c.GetChildById(id)
+	if f == nil {
+		f = newThemedDiv(c.currentTheme(), id, ThemeCardFooter)
+		c.AddChild(f)
+	}
+	for _, x := range children {
+		f.AddChild(x)
+	}
+	return c
+}
+
+// Tabs: a tab list "<id>-tablist" of buttons, and a panel for each tab. The tab and panel of the key
+// "k" are "<id>-tab-k" and "<id>-panel-k". Panels of tabs that are not selected are hidden.
+type Tabs struct {
+	*UIObject
+}
+
+// Create tabs, without any tab.
+func NewTabs(id string) *Tabs {
+	return GetUIConfig().NewTabs(id)
+}
+
+// Create tabs, as NewTabs, with the context theme.
+func (uic *UIContext) NewTabs(id string) *Tabs {
+	t := &Tabs{newThemedDiv(uic.theme, id)}
+	list := newThemedDiv(uic.theme, SelectorSafeId(id+"-tablist"), ThemeTabs)
+	list.SetRole("tablist")
+	t.AddChild(list)
+	return t
+}
+
+// Add a tab, with the children in its panel. The first tab is selected.
+func (t *Tabs) Tab(key, title string, children ...HTMLElementWriter) *Tabs {
+	tabId, panelId := SelectorSafeId(t.id+"-tab-"+key), SelectorSafeId(t.id+"-panel-"+key)
+	list := t.GetChildById(SelectorSafeId(t.id + "-tablist"))
+	if list == nil || t.GetChildById(panelId) != nil {
+		log.Printf("goui.Tab: can't add tab %q to %q", key, t.id)
+		return t
+	}
+	b := newThemedElement(t.currentTheme(), "button", tabId, title, ThemeTab)
+	b.attrs["type"] = "button"
+	b.attrs["data-tab"] = key
+	b.SetRole("tab")
+	b.SetAria("controls", panelId)
+	list.AddChild(b)
+
+	p := newThemedDiv(t.currentTheme(), panelId, ThemeTabPanel)
+	p.attrs["data-tab"] = key
+	p.SetRole("tabpanel")
+	p.SetAria("labelledby", tabId)
+	for _, x := range children {
+		p.AddChild(x)
+	}
+	t.AddChild(p)
+
+	first := list.ChildCount() == 1
+	t.markTab(b, p, first)
+	return t
+}
+
+// Select the tab with the key. An unknown key is logged, and the selection is unchanged.
+func (t *Tabs) Select(key string) *Tabs {
+	if t.GetChildById(SelectorSafeId(t.id+"-panel-"+key)) == nil {
+		log.Printf("goui.Select: %q has no tab %q", t.id, key)
+		return t
+	}
+	list := t.GetChildById(SelectorSafeId(t.id + "-tablist"))
+	for _, c := range list.ChildrenByOrder() {
+		b := toUIObject(c)
+		var p *UIObject
+		if x := t.GetChildById(SelectorSafeId(t.id + "-panel-" + b.attrs["data-tab"])); x != nil {
+			p = toUIObject(x)
+		}
+		t.markTab(b, p, b.attrs["data-tab"] == key)
+	}
+	return t
+}
+
+// Return the key of the selected tab, or "" if there are no tabs.
+func (t *Tabs) Selected() string {
+	if list := t.GetChildById(SelectorSafeId(t.id + "-tablist")); list != nil {
+		for _, c := range list.ChildrenByOrder() {
+			if c.Aria("selected") == "true" {
+				return c.GetAttribute("data-tab")
+			}
+		}
+	}
+	return ""
+}
+
+func (t *Tabs) markTab(b, p *UIObject, selected bool) {
+	b.SetAria("selected", selected)
+	if selected {
+		b.attrs["tabindex"] = "0"
+		b.AddThemeClass(ThemeTabActive)
+	} else {
+		b.attrs["tabindex"] = "-1"
+		b.RemoveThemeClass(ThemeTabActive)
+	}
+	if p != nil {
+		setBoolAttribute(p, "hidden", !selected)
+	}
+}
+
+// A modal dialog, hidden until shown by a script, or by Show, with the ThemeModalShown classes. The parts are the dialog
+// "<id>-dialog", the content "<id>-content", the header "<id>-header" with the title "<id>-title"
+// and the close button "<id>-close", the body "<id>-body" and the optional footer "<id>-footer".
+type Modal struct {
+	*UIObject
+}
+
+// Create a modal dialog with the title.
+func NewModal(id, title string) *Modal {
+	return GetUIConfig().NewModal(id, title)
+}
+
+// Create a modal dialog, as NewModal, with the context theme.
+func (uic *UIContext) NewModal(id, title string) *Modal {
+	th := uic.theme
+	m := &Modal{newThemedDiv(th, id, ThemeModal)}
+	m.SetRole("dialog")
+	m.SetAria("modal", true)
+	m.SetAria("labelledby", SelectorSafeId(id+"-title"))
+	m.attrs["tabindex"] = "-1"
+	m.AddThemeClass(ThemeModalHidden)
+
+	dialog := newThemedDiv(th, SelectorSafeId(id+"-dialog"), ThemeModalDialog)
+	m.AddChild(dialog)
+	content := newThemedDiv(th, SelectorSafeId(id+"-content"), ThemeModalContent)
+	dialog.AddChild(content)
+	header := newThemedDiv(th, SelectorSafeId(id+"-header"), ThemeModalHeader)
+	content.AddChild(header)
+	header.AddChild(newThemedElement(th, "h2", SelectorSafeId(id+"-title"), title, ThemeModalTitle))
+	header.AddChild(newDismissButton(th, SelectorSafeId(id+"-close"), "modal"))
+	content.AddChild(newThemedDiv(th, SelectorSafeId(id+"-body"), ThemeModalBody))
+	return m
+}
+
+// Return the body, or nil if it was removed.
+func (m *Modal) Body() *UIObject {
+	if x := m.SearchChildrenById(SelectorSafeId(m.id + "-body")); x != nil {
+		return toUIObject(x)
+	}
+	return nil
+}
+
+// Add children to the body. A modal without a body is logged, and is unchanged.
+func (m *Modal) Add(children ...HTMLElementWriter) *Modal {
+	body := m.Body()
+	if body == nil {
+		log.Printf("goui.Add: modal %q has no body", m.id)
+		return m
+	}
+	for _, x := range children {
+		body.AddChild(x)
+	}
+	return m
+}
+
+// Add children to the footer, e.g. buttons. The footer is created by the first call. A modal
+// without a content is logged, and is unchanged.
+func (m *Modal) Footer(children ...HTMLElementWriter) *Modal {
+	id := SelectorSafeId(m.id + "-footer")
+	f := m.SearchChildrenById(id)
+	if f == nil {
+		content := m.SearchChildrenById(SelectorSafeId(m.id + "-content"))
+		if content == nil {
+			log.Printf("goui.Footer: modal %q has no content", m.id)
+			return m
+		}
+		f = newThemedDiv(m.currentTheme(), id, ThemeModalFooter)
+		content.AddChild(f)
+	}
+	for _, x := range children {
+		f.AddChild(x)
+	}
+	return m
+}
+
+// Show or hide the dialog, e.g. to show it when the page loads.
+func (m *Modal) Show(on bool) *Modal {
+	if on {
+		m.RemoveThemeClass(ThemeModalHidden)
+		m.AddThemeClass(ThemeModalShown)
+	} else {
+		m.RemoveThemeClass(ThemeModalShown)
+		m.AddThemeClass(ThemeModalHidden)
+	}
+	return m
+}
+
+// An accordion of items that each have a button that shows or hides the item body. The item with
+// the key "k" is "<id>-k", its button "<id>-k-button" and its body "<id>-k-body".
+type Accordion struct {
+	*UIObject
+}
+
+// Create an accordion, without any item.
+func NewAccordion(id string) *Accordion {
+	return GetUIConfig().NewAccordion(id)
+}
+
+// Create an accordion, as NewAccordion, with the context theme.
+func (uic *UIContext) NewAccordion(id string) *Accordion {
+	return &Accordion{newThemedDiv(uic.theme, id, ThemeAccordion)}
+}
+
+// Add an item, with the children in its body. The item is closed.
+func (a *Accordion) Item(key, title string, children ...HTMLElementWriter) *Accordion {
+	id, th := SelectorSafeId(a.id+"-"+key), a.currentTheme()
+	item := newThemedDiv(th, id, ThemeAccordionItem)
+	h := NewElement("h3", SelectorSafeId(id+"-header"), "", "")
+	item.AddChild(h)
+	b := newThemedElement(th, "button", SelectorSafeId(id+"-button"), title, ThemeAccordionButton)
+	b.attrs["type"] = "button"
+	b.SetAria("expanded", false)
+	b.SetAria("controls", SelectorSafeId(id+"-body"))
+	h.AddChild(b)
+	body := newThemedDiv(th, SelectorSafeId(id+"-body"), ThemeAccordionBody)
+	body.SetRole("region")
+	body.SetAria("labelledby", b.id)
+	setBoolAttribute(body, "hidden", true)
+	for _, x := range children {
+		body.AddChild(x)
+	}
+	item.AddChild(body)
+	if err := a.AppendChild(item); err != nil {
+		log.Printf("goui.Item: %s", err)
+	}
+	return a
+}
+
+// Open or close the item with the key. An unknown key is logged.
+func (a *Accordion) Open(key string, on bool) *Accordion {
+	id := SelectorSafeId(a.id + "-" + key)
+	b, body := a.SearchChildrenById(id+"-button"), a.SearchChildrenById(id+"-body")
+	if b == nil || body == nil {
+		log.Printf("goui.Open: %q has no item %q", a.id, key)
+		return a
+	}
+	b.SetAria("expanded", on)
+	setBoolAttribute(toUIObject(body), "hidden", !on)
+	return a
+}
+
+// An alert message.
+type Alert struct {
+	*UIObject
+}
+
+// Create an alert with the text. The role is one of ThemeAlertInfo, ThemeAlertSuccess,
+// ThemeAlertWarning or ThemeAlertDanger.
+func NewAlert(id string, role ThemeRole, text string) *Alert {
+	return GetUIConfig().NewAlert(id, role, text)
+}
+
+// Create an alert, as NewAlert, with the context theme.
+func (uic *UIContext) NewAlert(id string, role ThemeRole, text string) *Alert {
+	a := &Alert{newThemedDiv(uic.theme, id, role)}
+	a.SetText(text)
+	a.SetRole("alert")
+	return a
+}
+
+// Add a close button, "<id>-close".
+func (a *Alert) Dismissible() *Alert {
+	a.AddChild(newDismissButton(a.currentTheme(), SelectorSafeId(a.id+"-close"), "alert"))
+	return a
+}
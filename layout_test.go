@@ -0,0 +1,175 @@
+package goui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func isHidden(ui HTMLElementWriter) bool {
+	_, ok := toUIObject(ui).attrs["hidden"]
+	return ok
+}
+
+func TestGrid(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		g := NewGrid("main")
+		g.Row().Col(8, NewElement("p", "intro", "", "Hello")).Col(4)
+		x := renderString(t, g.UIObject)
+		gotestutil.AssertStringsEqual(t, x, `<div id="main" class="container"><div id="main-div-1" class="row">`+
+			`<div id="main-div-1-div-1" class="col-md-8"><p id="intro">Hello</p></div>`+
+			`<div id="main-div-1-div-2" class="col-md-4"></div></div></div>`,
+			"Unexpected grid HTML. Actual: %s.", x)
+	})
+	t.Run("B1", func(t *testing.T) {
+		uic := NewUIContext().SetTheme("tailwind")
+		r := uic.NewGridRow("r").Col(13)
+		gotestutil.AssertStringsEqual(t, r.Class(), "grid grid-cols-12 gap-4", "Unexpected tailwind row.")
+		gotestutil.AssertStringsEqual(t, r.QueryOne("div").Class(), "col-span-12", "Expected a full width column.")
+	})
+}
+
+func TestCard(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		c := NewCard("profile", "Profile").Add(NewElement("p", "bio", "", "Gopher")).
+			Footer(NewElement("a", "help", "", "Help"))
+		x := renderString(t, c.UIObject)
+		gotestutil.AssertStringsEqual(t, x, `<div id="profile" class="card">`+
+			`<div id="profile-header" class="card-header">Profile</div>`+
+			`<div id="profile-body" class="card-body"><p id="bio">Gopher</p></div>`+
+			`<div id="profile-footer" class="card-footer"><a id="help">Help</a></div></div>`,
+			"Unexpected card HTML. Actual: %s.", x)
+	})
+	t.Run("B1", func(t *testing.T) {
+		c := NewCard("note", "").Footer().Footer()
+		gotestutil.AssertNil(t, c.GetChildById("note-header"), "Expected no header without a title.")
+		gotestutil.AssertEqual(t, c.ChildCount(), 2, "Expected one body and one footer.")
+
+		outer := NewCard("outer", "Outer")
+		outer.Add(c)
+		gotestutil.AssertNotNil(t, outer.SearchChildrenById("note-body"), "Expected a nested card.")
+
+		c.RemoveChild("note-body")
+		gotestutil.AssertTrue(t, c.Body() == nil, "Expected no body.")
+		c.Add(NewElement("p", "lost", "", "Lost"))
+		gotestutil.AssertNil(t, c.SearchChildrenById("lost"), "Expected nothing added without a body.")
+	})
+}
+
+func TestTabs(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		tabs := NewTabs("settings").Tab("general", "General", NewElement("p", "", "", "One")).Tab("mail", "Mail")
+		gotestutil.AssertStringsEqual(t, tabs.Selected(), "general", "Expected the first tab selected.")
+		x := renderString(t, tabs.UIObject)
+		gotestutil.AssertStringsEqual(t, x, `<div id="settings">`+
+			`<div id="settings-tablist" class="nav nav-tabs" role="tablist">`+
+			`<button id="settings-tab-general" class="nav-link active" aria-controls="settings-panel-general" `+
+			`aria-selected="true" data-tab="general" role="tab" tabindex="0" type="button">General</button>`+
+			`<button id="settings-tab-mail" class="nav-link" aria-controls="settings-panel-mail" `+
+			`aria-selected="false" data-tab="mail" role="tab" tabindex="-1" type="button">Mail</button></div>`+
+			`<div id="settings-panel-general" aria-labelledby="settings-tab-general" data-tab="general" role="tabpanel">`+
+			`<p id="settings-panel-general-p-1">One</p></div>`+
+			`<div id="settings-panel-mail" aria-labelledby="settings-tab-mail" data-tab="mail" hidden role="tabpanel"></div></div>`,
+			"Unexpected tabs HTML. Actual: %s.", x)
+
+		tabs.Select("mail")
+		gotestutil.AssertStringsEqual(t, tabs.Selected(), "mail", "Expected mail selected.")
+		gotestutil.AssertFalse(t, tabs.SearchChildrenById("settings-tab-general").HasClass("active"),
+			"Expected the active class cleared.")
+		gotestutil.AssertTrue(t, isHidden(tabs.GetChildById("settings-panel-general")),
+			"Expected the general panel hidden.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		tabs := NewTabs("t").Tab("a", "A").Tab("a", "Again")
+		gotestutil.AssertEqual(t, tabs.ChildCount(), 2, "Expected a duplicate tab ignored.")
+		tabs.Select("nosuchtab")
+		gotestutil.AssertStringsEqual(t, tabs.Selected(), "a", "Expected an unknown tab ignored.")
+		gotestutil.AssertEmptyString(t, NewTabs("empty").Selected(), "Expected no selected tab.")
+	})
+}
+
+func TestModal(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		m := NewModal("confirm", "Delete?").Add(NewElement("p", "msg", "", "This can't be undone.")).
+			Footer(NewElement("button", "yes", "", "Delete"))
+		x := renderString(t, m.UIObject)
+		gotestutil.AssertStringsEqual(t, x, `<div id="confirm" class="modal" aria-labelledby="confirm-title" `+
+			`aria-modal="true" role="dialog" tabindex="-1">`+
+			`<div id="confirm-dialog" class="modal-dialog"><div id="confirm-content" class="modal-content">`+
+			`<div id="confirm-header" class="modal-header"><h2 id="confirm-title" class="modal-title">Delete?</h2>`+
+			`<button id="confirm-close" class="btn-close" aria-label="Close" data-dismiss="modal" type="button">×</button></div>`+
+			`<div id="confirm-body" class="modal-body"><p id="msg">This can&#39;t be undone.</p></div>`+
+			`<div id="confirm-footer" class="modal-footer"><button id="yes">Delete</button></div></div></div></div>`,
+			"Unexpected modal HTML. Actual: %s.", x)
+	})
+	t.Run("A2", func(t *testing.T) {
+		uic := NewUIContext()
+		m := uic.NewModal("m", "Title").Show(true)
+		gotestutil.AssertStringsEqual(t, m.Class(), "modal show d-block", "Expected the modal shown.")
+		gotestutil.AssertStringsEqual(t, m.Show(false).Class(), "modal", "Expected the modal hidden.")
+
+		uic.SetTheme("bulma")
+		m = uic.NewModal("m", "Title")
+		gotestutil.AssertStringsEqual(t, m.Class(), "modal", "Expected the bulma modal hidden.")
+		gotestutil.AssertStringsEqual(t, m.Show(true).Class(), "modal is-active", "Expected the bulma modal shown.")
+
+		uic.SetTheme("tailwind")
+		m = uic.NewModal("m", "Title")
+		gotestutil.AssertTrue(t, m.HasClass("hidden") && !m.HasClass("flex"), "Expected the tailwind modal hidden.")
+		m.Show(true)
+		gotestutil.AssertTrue(t, m.HasClass("flex") && !m.HasClass("hidden"), "Expected the tailwind modal shown.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		m := NewModal("m", "Title").Show(true)
+		gotestutil.AssertNil(t, m.SearchChildrenById("m-footer"), "Expected no footer.")
+
+		m.SearchChildrenById("m-content").RemoveChild("m-body")
+		gotestutil.AssertTrue(t, m.Body() == nil, "Expected no body.")
+		m.Add(NewElement("p", "msg", "", "Lost"))
+		gotestutil.AssertNil(t, m.SearchChildrenById("msg"), "Expected nothing added without a body.")
+		m.SearchChildrenById("m-dialog").RemoveChild("m-content")
+		m.Footer(NewElement("button", "ok", "", "OK"))
+		gotestutil.AssertNil(t, m.SearchChildrenById("ok"), "Expected nothing added without a content.")
+	})
+}
+
+func TestAccordion(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		a := NewAccordion("faq").Item("cost", "What does it cost?", NewElement("p", "free", "", "Nothing.")).
+			Item("where", "Where?")
+		a.Open("cost", true)
+		x := renderString(t, a.GetChildById("faq-cost").(*UIObject))
+		gotestutil.AssertStringsEqual(t, x, `<div id="faq-cost" class="accordion-item">`+
+			`<h3 id="faq-cost-header"><button id="faq-cost-button" class="accordion-button" aria-controls="faq-cost-body" `+
+			`aria-expanded="true" type="button">What does it cost?</button></h3>`+
+			`<div id="faq-cost-body" class="accordion-body" aria-labelledby="faq-cost-button" role="region">`+
+			`<p id="free">Nothing.</p></div></div>`,
+			"Unexpected accordion item HTML. Actual: %s.", x)
+		gotestutil.AssertTrue(t, isHidden(a.SearchChildrenById("faq-where-body")), "Expected where closed.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		a := NewAccordion("faq").Item("cost", "Cost").Item("cost", "Again")
+		gotestutil.AssertEqual(t, a.ChildCount(), 1, "Expected a duplicate item ignored.")
+		a.Open("nosuchitem", true).Open("cost", true).Open("cost", false)
+		gotestutil.AssertStringsEqual(t, a.SearchChildrenById("faq-cost-button").Aria("expanded"), "false",
+			"Expected the item closed.")
+	})
+}
+
+func TestAlert(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		x := renderString(t, NewAlert("saved", ThemeAlertSuccess, "Saved.").Dismissible().UIObject)
+		gotestutil.AssertStringsEqual(t, x, `<div id="saved" class="alert alert-success" role="alert">Saved.`+
+			`<button id="saved-close" class="btn-close" aria-label="Close" data-dismiss="alert" type="button">×</button></div>`,
+			"Unexpected alert HTML. Actual: %s.", x)
+	})
+	t.Run("B1", func(t *testing.T) {
+		uic := NewUIContext().SetTheme("bulma")
+		a := uic.NewAlert("err", ThemeAlertDanger, "<b>Failed</b>").Dismissible()
+		gotestutil.AssertStringsEqual(t, a.Class(), "notification is-danger", "Unexpected bulma alert.")
+		gotestutil.AssertStringsEqual(t, a.GetChildById("err-close").Class(), "delete", "Unexpected bulma close button.")
+		gotestutil.AssertTrue(t, strings.Contains(renderString(t, a.UIObject), "&lt;b&gt;Failed"),
+			"Expected the alert text escaped.")
+	})
+}
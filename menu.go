@@ -0,0 +1,201 @@
+package goui
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Navigation menus: a ContentTypeMenu of ContentTypeLink items, separators and nested submenus.
+// A menu is usually built once, and a copy is marked for each request.
+//
+// Example:
+//     nav := NewMenu("nav").Link("home", "Home", "/").Link("blog", "Blog", "/blog")
+//     nav.Submenu("admin", "Admin", "admin").Link("users", "Users", "/admin/users")
+//     ...
+//     page.AddNavigation(nav.ForRequest(r, user.Roles...))
+
+const (
+	// The class of the link to the current page.
+	MenuActiveClass = "active"
+	// The class of the submenus that contain the active link.
+	MenuOpenClass = "open"
+	// The attribute with the roles that can see an item, separated by spaces.
+	MenuRolesAttribute = "data-roles"
+)
+
+// A navigation menu, rendered as a <ul> with an <li> for each item.
+type Menu struct {
+	*UIObject
+}
+
+// Create an empty menu.
+func NewMenu(id string) *Menu {
+	return GetUIConfig().NewMenu(id)
+}
+
+// Create an empty menu, as NewMenu, with the context IdGenerator and theme.
+func (uic *UIContext) NewMenu(id string) *Menu {
+	return &Menu{uic.NewElement(ContentTypeMenu, id, "", "")}
+}
+
+// Use an existing element, e.g. one read with NewElementFromJSON, as a menu.
+func AsMenu(ui HTMLElementWriter) *Menu {
+	return &Menu{toUIObject(ui)}
+}
+
+// Restrict an item to users with one of the roles. Without roles, the item is visible to everyone.
+func setMenuRoles(uio *UIObject, roles []string) {
+	if len(roles) == 0 {
+		delete(uio.attrs, MenuRolesAttribute)
+		return
+	}
+	uio.attrs[MenuRolesAttribute] = strings.Join(roles, " ")
+}
+
+// Add a link. If roles are given, only users with one of the roles see the link. See VisibleTo.
+func (m *Menu) Link(id, text, href string, roles ...string) *Menu {
+	a := NewElement(ContentTypeLink, id, "", text)
+	a.attrs["href"] = href
+	setMenuRoles(a, roles)
+	m.AddChild(a)
+	return m
+}
+
+// Add a separator.
+func (m *Menu) Separator(id string) *Menu {
+	m.AddChild(NewElement(ContentTypeSeparator, id, "", ""))
+	return m
+}
+
+// Add a submenu with the caption text, and return it. If roles are given, only users with one of
+// the roles see the submenu.
+func (m *Menu) Submenu(id, text string, roles ...string) *Menu {
+	sub := NewMenu(id)
+	sub.SetText(text)
+	setMenuRoles(sub.UIObject, roles)
+	m.AddChild(sub)
+	return sub
+}
+
+// Restrict the item with the id, anywhere in the menu, to users with one of the roles. Without roles,
+// the item is visible to everyone. Returns ErrNotFound if there is no item with the id.
+func (m *Menu) SetRoles(id string, roles ...string) error {
+	x := m.SearchChildrenById(id)
+	if x == nil {
+		return errorf("SetRoles, no menu item "+id, ErrNotFound)
+	}
+	setMenuRoles(toUIObject(x), roles)
+	return nil
+}
+
+// Return a copy of the menu with only the items visible to a user with the roles. Items without
+// roles are visible to everyone, and submenus left with no items are removed. The copy has no role
+// attributes, so the roles are not sent to the browser. If the menu itself is restricted to other
+// roles, the copy has no items.
+func (m *Menu) VisibleTo(roles ...string) *Menu {
+	c := m.DeepCopy()
+	x := c.Transform(func(el HTMLElementWriter) HTMLElementWriter {
+		uio := toUIObject(el)
+		allowed, restricted := uio.attrs[MenuRolesAttribute]
+		delete(uio.attrs, MenuRolesAttribute)
+		if restricted && !rolesIntersect(strings.Fields(allowed), roles) {
+			return nil
+		}
+		if uio != c && uio.contentType == ContentTypeMenu && uio.ChildCount() == 0 {
+			// A submenu with all items hidden.
+			return nil
+		}
+		return el
+	})
+	if x == nil {
+		for _, ch := range c.ChildrenByOrder() {
+			c.RemoveChild(ch.Id())
+		}
+	}
+	return &Menu{c}
+}
+
+func rolesIntersect(a, b []string) bool {
+	for _, r := range a {
+		if containsString(b, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mark the link that best matches the request path with the MenuActiveClass class, the theme
+// ThemeNavActive classes and aria-current, and the submenus that contain it with the MenuOpenClass class. Marks from an
+// earlier request are cleared. The best match is the link with the same path, or else the link
+// with the longest path that is a parent of the request path, e.g. "/blog" for "/blog/2017/go".
+// Links to other hosts are not matched, and "/" only matches itself.
+//
+// MarkActive changes the menu. For a menu that is shared by requests, use ForRequest.
+func (m *Menu) MarkActive(r *http.Request) *Menu {
+	m.walk(func(uio *UIObject, depth int) WalkAction {
+		uio.RemoveCssClass(MenuActiveClass + " " + MenuOpenClass)
+		uio.RemoveThemeClass(ThemeNavActive)
+		if uio.attrs["aria-current"] == "page" {
+			delete(uio.attrs, "aria-current")
+		}
+		return WalkContinue
+	}, 0)
+	best, exact := matchMenuLink(m.UIObject, r)
+	if best == nil {
+		return m
+	}
+	best.AddCssClass(MenuActiveClass)
+	best.AddThemeClass(ThemeNavActive)
+	if exact {
+		best.attrs["aria-current"] = "page"
+	}
+	for p := best.parent; p != nil && p != m.UIObject; p = p.parent {
+		if p.contentType == ContentTypeMenu {
+			p.AddCssClass(MenuOpenClass)
+		}
+	}
+	return m
+}
+
+// Return the link in the tree that best matches the request path, as described for MarkActive, or
+// nil if no link matches. exact is true if the link path is the request path.
+func matchMenuLink(root *UIObject, r *http.Request) (best *UIObject, exact bool) {
+	reqPath := cleanMenuPath(r.URL.Path)
+	bestLen := -1
+	root.walk(func(uio *UIObject, depth int) WalkAction {
+		if uio.contentType != ContentTypeLink {
+			return WalkContinue
+		}
+		href, err := url.Parse(uio.attrs["href"])
+		if err != nil || (len(href.Host) > 0 && href.Host != r.Host) {
+			return WalkContinue
+		}
+		p := cleanMenuPath(href.Path)
+		if len(href.Path) == 0 || len(p) <= bestLen {
+			return WalkContinue
+		}
+		if p == reqPath || (p != "/" && strings.HasPrefix(reqPath, p+"/")) {
+			best, bestLen, exact = uio, len(p), p == reqPath
+		}
+		return WalkContinue
+	}, 0)
+	return best, exact
+}
+
+func cleanMenuPath(p string) string {
+	if u, err := url.Parse(p); err == nil {
+		p = u.Path
+	}
+	if len(p) == 0 {
+		return "/"
+	}
+	return path.Clean("/" + p)
+}
+
+// Return a copy of the menu for a request: visible to a user with the roles, and marked with
+// MarkActive. The menu is not changed, so it can be shared by requests.
+func (m *Menu) ForRequest(r *http.Request, roles ...string) *Menu {
+	return m.VisibleTo(roles...).MarkActive(r)
+}
@@ -0,0 +1,65 @@
+package goui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func newTestNav() *Menu {
+	nav := NewMenu("nav").Link("home", "Home", "/").Link("blog", "Blog", "/blog/")
+	admin := nav.Submenu("admin", "Admin", "admin", "editor")
+	admin.Link("users", "Users", "/admin/users", "admin").Link("posts", "Posts", "/admin/posts")
+	return nav
+}
+
+func TestMenuMarkActive(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		nav := newTestNav()
+		nav.MarkActive(httptest.NewRequest(http.MethodGet, "/admin/users?page=2", nil))
+		gotestutil.AssertTrue(t, nav.SearchChildrenById("users").HasClass(MenuActiveClass), "Expected users active.")
+		gotestutil.AssertStringsEqual(t, nav.SearchChildrenById("users").Aria("current"), "page",
+			"Expected aria-current on an exact match.")
+		gotestutil.AssertTrue(t, nav.SearchChildrenById("admin").HasClass(MenuOpenClass), "Expected admin open.")
+		gotestutil.AssertFalse(t, nav.HasClass(MenuOpenClass), "Expected the root menu not open.")
+
+		nav.MarkActive(httptest.NewRequest(http.MethodGet, "/blog/2017/go", nil))
+		gotestutil.AssertTrue(t, nav.SearchChildrenById("blog").HasClass(MenuActiveClass), "Expected blog active.")
+		gotestutil.AssertEmptyString(t, nav.SearchChildrenById("blog").Aria("current"),
+			"Expected no aria-current on a parent path.")
+		gotestutil.AssertFalse(t, nav.SearchChildrenById("users").HasClass(MenuActiveClass), "Expected users cleared.")
+		gotestutil.AssertFalse(t, nav.SearchChildrenById("admin").HasClass(MenuOpenClass), "Expected admin cleared.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		nav := newTestNav()
+		nav.MarkActive(httptest.NewRequest(http.MethodGet, "/about", nil))
+		gotestutil.AssertFalse(t, nav.SearchChildrenById("home").HasClass(MenuActiveClass),
+			"Expected / to only match itself.")
+	})
+}
+
+func TestMenuForRequest(t *testing.T) {
+	nav := newTestNav()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	x := renderString(t, nav.ForRequest(r, "editor").UIObject)
+	gotestutil.AssertStringsEqual(t, x, `<ul id="nav"><li><a id="home" class="active" aria-current="page" href="/">Home</a></li>`+
+		`<li><a id="blog" href="/blog/">Blog</a></li><li><span>Admin</span><ul id="admin">`+
+		`<li><a id="posts" href="/admin/posts">Posts</a></li></ul></li></ul>`, "Unexpected menu HTML. Actual: %s.", x)
+	gotestutil.AssertFalse(t, nav.SearchChildrenById("home").HasClass(MenuActiveClass), "Expected the menu unchanged.")
+
+	guest := nav.ForRequest(r)
+	gotestutil.AssertNil(t, guest.SearchChildrenById("admin"), "Expected admin hidden from guests.")
+
+	nav.SetRoles("posts", "admin")
+	gotestutil.AssertNil(t, nav.VisibleTo("editor").SearchChildrenById("admin"), "Expected an empty submenu removed.")
+	nav.SetRoles("nav", "admin")
+	hidden := nav.VisibleTo("editor")
+	gotestutil.AssertEqual(t, hidden.ChildCount(), 0, "Expected no items in a menu restricted to other roles.")
+	gotestutil.AssertEmptyString(t, hidden.GetAttribute(MenuRolesAttribute), "Expected no roles attribute.")
+	gotestutil.AssertEqual(t, nav.VisibleTo("admin").ChildCount(), 3, "Expected the menu visible to its roles.")
+	err := nav.SetRoles("none")
+	gotestutil.AssertTrue(t, err != nil && err.(*Error).Err == ErrNotFound, "Expected ErrNotFound. %v", err)
+}
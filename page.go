@@ -4,12 +4,15 @@ import (
 	"html/template"
 	"net/http"
 	"fmt"
+	"log"
 )
 
 const (
 	PageData = "Data"
 	PageTitle = "Title"
 	PageNav = "Nav"
+	PageCSRF = "CSRF"
+	PageBreadcrumbs = "Breadcrumbs"
 )
 
 //
@@ -38,7 +41,9 @@ func NewPage(uic *UIContext, title string, defTmpl string) *UIPage{
 // Execute the page rendering with data. If a template name is provided, then that template is rendered.
 // If no template name is provided, then it first checks to see if the page's default template is non empty.
 // If there is no default page template, the default template for the configuration is used.
-// The PageData field is used to render the template.
+// The PageData field is used to render the template. If the page has a CSRF token, from AddCSRFToken,
+// or the writer is from CSRFMiddleware, the token is the {{.CSRF}} pipeline, and the forms in the
+// elements of PageData are rendered with the token.
 func (uip *UIPage) ExecuteTemplate(wr http.ResponseWriter, tmplName string) error {
 	tmpl := tmplName
 	if len(tmpl) == 0 {
@@ -49,7 +54,11 @@ func (uip *UIPage) ExecuteTemplate(wr http.ResponseWriter, tmplName string) erro
 		}
 	}
 	// Execute the page with the data
-	if err := uip.t.ExecuteTemplate(wr, tmpl, uip.PageData); err != nil {
+	token, _ := uip.PageData[PageCSRF].(string)
+	if len(token) == 0 {
+		token = csrfWriterToken(wr)
+	}
+	if err := uip.t.ExecuteTemplate(wr, tmpl, uip.pageDataWithCSRF(token)); err != nil {
 		return errorf("Error on Execute.", err)
 	}
 	return nil
@@ -93,3 +102,34 @@ func (uip *UIPage) AddNavigation(uio HTMLElementWriter) *UIPage {
 func (uip *UIPage) Navigation() HTMLElementWriter {
 	return uip.PageData[PageNav].(HTMLElementWriter)
 }
+
+// Add the CSRF token of the request, from CSRFMiddleware, to the page. Templates access the token
+// with the {{.CSRF}} pipeline, and forms in the page elements get a hidden input with the token.
+// Only needed when the page is not rendered to the writer from CSRFMiddleware, e.g. if another
+// middleware replaces the writer.
+func (uip *UIPage) AddCSRFToken(r *http.Request) *UIPage {
+	token := CSRFToken(r)
+	if len(token) == 0 {
+		log.Printf("goui.AddCSRFToken: no CSRF token for %s, use CSRFMiddleware.", r.URL.Path)
+		return uip
+	}
+	uip.PageData[PageCSRF] = token
+	return uip
+}
+
+// Return the page data for rendering. With a CSRF token, elements that contain forms are
+// replaced by copies with the token, so the page elements can be shared by requests.
+func (uip *UIPage) pageDataWithCSRF(token string) map[string]interface{} {
+	if len(token) == 0 {
+		return uip.PageData
+	}
+	data := make(map[string]interface{}, len(uip.PageData))
+	for k, v := range uip.PageData {
+		if ui, ok := v.(HTMLElementWriter); ok && ui != nil {
+			v = withCSRFToken(ui, token)
+		}
+		data[k] = v
+	}
+	data[PageCSRF] = token
+	return data
+}
@@ -0,0 +1,418 @@
+package goui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// CSS selector queries over an element tree.
+//
+// Supported syntax:
+//     type           content type or HTML tag, e.g. submit_input, link, a, input, form. "*" matches any element.
+//     #id            element id
+//     .class         CSS class name
+//     [attr]         attribute is present
+//     [attr=value]   attribute value, optionally quoted
+//     :nth-child(n)  position among the parent's ordered children. n is a number, odd, even, or an+b
+//     A B            B is a descendant of A
+//     A > B          B is a child of A
+//     A, B           elements matching A or B
+
+var ErrSelector = errors.New("invalid selector")
+
+const (
+	combinatorDescendant = ' '
+	combinatorChild      = '>'
+)
+
+// An attribute condition, e.g. [href] or [href="/"]
+type attrSelector struct {
+	name     string
+	value    string
+	hasValue bool
+}
+
+// The nth-child condition, an+b
+type nthSelector struct {
+	a, b int
+}
+
+// A compound selector, e.g. form.checkout[method=post]
+type compoundSelector struct {
+	typeName string
+	id       string
+	classes  []string
+	attrs    []attrSelector
+	nth      []nthSelector
+	// How this compound relates to the previous one in the complex selector.
+	combinator byte
+}
+
+// A sequence of compound selectors joined by combinators, e.g. form.checkout > submit_input
+type complexSelector []compoundSelector
+
+// Return all descendants of the element that match the selector, in document order.
+// The element itself is not included in the results, but can match the ancestor part of a selector.
+// An invalid selector is logged and returns no elements. Use QueryE to check the selector.
+// Example: uio.Query("form.checkout submit_input")
+func (he *UIObject) Query(selector string) []HTMLElementWriter {
+	x, err := he.QueryE(selector)
+	if err != nil {
+		log.Printf("goui.Query: %s", err)
+	}
+	return x
+}
+
+// Return all descendants of the element that match the selector, as Query.
+// Returns ErrSelector if the selector is invalid.
+func (he *UIObject) QueryE(selector string) ([]HTMLElementWriter, error) {
+	sel, err := parseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	var x []HTMLElementWriter
+	he.queryChildren(sel, []*UIObject{he}, []int{0}, &x)
+	return x, nil
+}
+
+// Return the first descendant of the element that matches the selector, or nil if there is no match.
+func (he *UIObject) QueryOne(selector string) HTMLElementWriter {
+	if x := he.Query(selector); len(x) > 0 {
+		return x[0]
+	}
+	return nil
+}
+
+func (he *UIObject) queryChildren(sel []complexSelector, path []*UIObject, pos []int, x *[]HTMLElementWriter) {
+	i := 0
+	for l := he.childOrder.Front(); l != nil; l = l.Next() {
+		i++
+		c := l.Value.(*UIObject)
+		cPath := append(path, c)
+		cPos := append(pos, i)
+		for _, s := range sel {
+			if s.match(cPath, cPos, len(s)-1, len(cPath)-1) {
+				*x = append(*x, c)
+				break
+			}
+		}
+		c.queryChildren(sel, cPath[:len(cPath):len(cPath)], cPos[:len(cPos):len(cPos)], x)
+	}
+}
+
+// Match the compound selector at index si against path element pi, then the remaining compounds
+// against the ancestors of pi. pos holds the 1-based child position for each path element.
+func (cs complexSelector) match(path []*UIObject, pos []int, si, pi int) bool {
+	if !cs[si].match(path[pi], pos[pi]) {
+		return false
+	}
+	if si == 0 {
+		return true
+	}
+	switch cs[si].combinator {
+	case combinatorChild:
+		return pi > 0 && cs.match(path, pos, si-1, pi-1)
+	default:
+		for a := pi - 1; a >= 0; a-- {
+			if cs.match(path, pos, si-1, a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cs *compoundSelector) match(uio *UIObject, pos int) bool {
+	if len(cs.typeName) > 0 && cs.typeName != "*" && cs.typeName != uio.contentType &&
+		cs.typeName != tagForContentType(uio.contentType).name {
+		return false
+	}
+	if len(cs.id) > 0 && cs.id != uio.id {
+		return false
+	}
+	classes := uio.ClassList()
+	for _, c := range cs.classes {
+		if !containsString(classes, c) {
+			return false
+		}
+	}
+	for _, a := range cs.attrs {
+		v, ok := selectorAttribute(uio, a.name)
+		if !ok || (a.hasValue && v != a.value) {
+			return false
+		}
+	}
+	for _, n := range cs.nth {
+		if !n.match(pos) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n nthSelector) match(pos int) bool {
+	if pos < 1 {
+		return false
+	}
+	if n.a == 0 {
+		return pos == n.b
+	}
+	k := pos - n.b
+	return k%n.a == 0 && k/n.a >= 0
+}
+
+// Attribute lookup for [attr] conditions. The id and class fields are treated as attributes.
+func selectorAttribute(uio *UIObject, name string) (string, bool) {
+	switch name {
+	case "id":
+		return uio.id, len(uio.id) > 0
+	case "class":
+		return uio.class, len(uio.class) > 0
+	}
+	v, ok := uio.attrs[name]
+	return v, ok
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse a selector list, e.g. "form.checkout > submit_input, #cancel"
+func parseSelector(s string) ([]complexSelector, error) {
+	p := &selectorParser{s: s}
+	var list []complexSelector
+	for {
+		cs, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, cs)
+		p.skipSpace()
+		if p.eof() {
+			return list, nil
+		}
+		if p.peek() != ',' {
+			return nil, p.errorf("unexpected %q", p.peek())
+		}
+		p.i++
+	}
+}
+
+type selectorParser struct {
+	s string
+	i int
+}
+
+func (p *selectorParser) eof() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *selectorParser) peek() byte {
+	return p.s[p.i]
+}
+
+func (p *selectorParser) skipSpace() bool {
+	start := p.i
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\n') {
+		p.i++
+	}
+	return p.i > start
+}
+
+func (p *selectorParser) errorf(format string, args ...interface{}) error {
+	return errorf(fmt.Sprintf("Selector %q at %d: %s", p.s, p.i, fmt.Sprintf(format, args...)), ErrSelector)
+}
+
+func (p *selectorParser) parseComplex() (complexSelector, error) {
+	var cs complexSelector
+	p.skipSpace()
+	comb := byte(combinatorDescendant)
+	for {
+		c, err := p.parseCompound()
+		if err != nil {
+			return nil, err
+		}
+		c.combinator = comb
+		cs = append(cs, c)
+
+		space := p.skipSpace()
+		if p.eof() || p.peek() == ',' {
+			return cs, nil
+		}
+		if p.peek() == '>' {
+			comb = combinatorChild
+			p.i++
+			p.skipSpace()
+		} else if space {
+			comb = combinatorDescendant
+		} else {
+			return nil, p.errorf("unexpected %q", p.peek())
+		}
+	}
+}
+
+func (p *selectorParser) parseCompound() (compoundSelector, error) {
+	var c compoundSelector
+	start := p.i
+	if !p.eof() && p.peek() == '*' {
+		c.typeName = "*"
+		p.i++
+	} else {
+		c.typeName = p.parseName()
+	}
+	for !p.eof() {
+		switch p.peek() {
+		case '#':
+			p.i++
+			if c.id = p.parseName(); len(c.id) == 0 {
+				return c, p.errorf("expected id")
+			}
+		case '.':
+			p.i++
+			n := p.parseName()
+			if len(n) == 0 {
+				return c, p.errorf("expected class name")
+			}
+			c.classes = append(c.classes, n)
+		case '[':
+			p.i++
+			a, err := p.parseAttr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, a)
+		case ':':
+			p.i++
+			n, err := p.parsePseudo()
+			if err != nil {
+				return c, err
+			}
+			c.nth = append(c.nth, n)
+		default:
+			if p.i == start {
+				return c, p.errorf("expected selector")
+			}
+			return c, nil
+		}
+	}
+	if p.i == start {
+		return c, p.errorf("expected selector")
+	}
+	return c, nil
+}
+
+func isNameChar(b byte) bool {
+	return b == '_' || b == '-' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+func (p *selectorParser) parseName() string {
+	start := p.i
+	for !p.eof() && isNameChar(p.peek()) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+// Parse the remainder of an attribute condition after the opening bracket.
+func (p *selectorParser) parseAttr() (attrSelector, error) {
+	var a attrSelector
+	p.skipSpace()
+	if a.name = p.parseName(); len(a.name) == 0 {
+		return a, p.errorf("expected attribute name")
+	}
+	p.skipSpace()
+	if p.eof() {
+		return a, p.errorf("expected ]")
+	}
+	if p.peek() == '=' {
+		p.i++
+		p.skipSpace()
+		a.hasValue = true
+		if p.eof() {
+			return a, p.errorf("expected attribute value")
+		}
+		if q := p.peek(); q == '"' || q == '\'' {
+			end := strings.IndexByte(p.s[p.i+1:], q)
+			if end < 0 {
+				return a, p.errorf("unterminated string")
+			}
+			a.value = p.s[p.i+1 : p.i+1+end]
+			p.i += end + 2
+		} else {
+			a.value = p.parseName()
+		}
+		p.skipSpace()
+	}
+	if p.eof() || p.peek() != ']' {
+		return a, p.errorf("expected ]")
+	}
+	p.i++
+	return a, nil
+}
+
+// Parse a pseudo-class after the colon. Only :nth-child() is supported.
+func (p *selectorParser) parsePseudo() (nthSelector, error) {
+	var n nthSelector
+	if name := p.parseName(); name != "nth-child" {
+		return n, p.errorf("unsupported pseudo-class %q", name)
+	}
+	if p.eof() || p.peek() != '(' {
+		return n, p.errorf("expected (")
+	}
+	end := strings.IndexByte(p.s[p.i:], ')')
+	if end < 0 {
+		return n, p.errorf("expected )")
+	}
+	arg := p.s[p.i+1 : p.i+end]
+	p.i += end + 1
+	n, ok := parseNth(arg)
+	if !ok {
+		return n, p.errorf("invalid nth-child argument %q", arg)
+	}
+	return n, nil
+}
+
+// Parse the an+b argument of :nth-child, including the odd and even keywords.
+func parseNth(s string) (nthSelector, bool) {
+	s = strings.ToLower(strings.Replace(s, " ", "", -1))
+	switch s {
+	case "odd":
+		return nthSelector{a: 2, b: 1}, true
+	case "even":
+		return nthSelector{a: 2, b: 0}, true
+	}
+	ni := strings.IndexByte(s, 'n')
+	if ni < 0 {
+		b, err := strconv.Atoi(s)
+		return nthSelector{b: b}, err == nil
+	}
+	var n nthSelector
+	switch as := s[:ni]; as {
+	case "", "+":
+		n.a = 1
+	case "-":
+		n.a = -1
+	default:
+		a, err := strconv.Atoi(as)
+		if err != nil {
+			return n, false
+		}
+		n.a = a
+	}
+	if bs := s[ni+1:]; len(bs) > 0 {
+		b, err := strconv.Atoi(bs)
+		if err != nil {
+			return n, false
+		}
+		n.b = b
+	}
+	return n, true
+}
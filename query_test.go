@@ -0,0 +1,75 @@
+package goui
+
+import (
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+const testFormJSON = `{"id":"page", "type":"div", "children":[
+	{"id":"f1", "type":"form", "class":"checkout wide", "attributes":{"method":"post"}, "children":[
+		{"id":"email", "type":"email_input", "attributes":{"name":"email"}},
+		{"id":"grp", "type":"div", "children":[
+			{"id":"ok", "type":"submit_input", "class":"btn btn-primary"},
+			{"id":"cancel", "type":"reset_input", "class":"btn"}]},
+		{"id":"save", "type":"submit_input", "class":"btn"}]},
+	{"id":"f2", "type":"form", "children":[
+		{"id":"other", "type":"submit_input"}]}]}`
+
+func queryIds(x []HTMLElementWriter) []string {
+	ids := []string{}
+	for _, v := range x {
+		ids = append(ids, v.Id())
+	}
+	return ids
+}
+
+func TestUIObject_Query(t *testing.T) {
+	page, err := NewElementFromJSON(testFormJSON)
+	if err != nil {
+		t.Fatalf("Error on NewElementFromJSON: %s.\n", err)
+	}
+
+	tests := []struct {
+		selector string
+		ids      []string
+	}{
+		{"form.checkout submit_input", []string{"ok", "save"}},
+		{"form.checkout > submit_input", []string{"save"}},
+		{"#grp > .btn", []string{"ok", "cancel"}},
+		{".btn.btn-primary", []string{"ok"}},
+		{"input[name=email]", []string{"email"}},
+		{"form[method='post']", []string{"f1"}},
+		{"[method]", []string{"f1"}},
+		{"#f1 > :nth-child(2)", []string{"grp"}},
+		{"#grp > *:nth-child(odd)", []string{"ok"}},
+		{"form:nth-child(n+2) input", []string{"other"}},
+		{"#cancel, #email", []string{"email", "cancel"}},
+		{"link", []string{}},
+	}
+	for _, v := range tests {
+		ids := queryIds(page.Query(v.selector))
+		gotestutil.AssertEqual(t, ids, v.ids, "Unexpected result for %q. Actual: %v.", v.selector, ids)
+	}
+
+	t.Run("B1", func(t *testing.T) {
+		for _, s := range []string{"", "form >", "[name", ":first-child", "#", "a:nth-child(x)"} {
+			_, err := parseSelector(s)
+			gotestutil.AssertNotNil(t, err, "Expected error for selector %q.", s)
+		}
+		page, _ := NewElementFromJSON(testFormJSON)
+		x, err := page.QueryE("form >")
+		gotestutil.AssertEqual(t, len(x), 0, "Expected no elements for an invalid selector.")
+		gotestutil.AssertTrue(t, err != nil && err.(*Error).Err == ErrSelector, "Expected ErrSelector.")
+		x, err = page.QueryE("#dummy")
+		gotestutil.AssertTrue(t, err == nil && len(x) == 0, "Expected no error and no match for \"#dummy\".")
+	})
+}
+
+func TestUIObject_QueryOne(t *testing.T) {
+	page, _ := NewElementFromJSON(testFormJSON)
+	x := page.QueryOne("submit_input")
+	gotestutil.AssertNotNil(t, x, "Expected valid element for \"submit_input\".")
+	gotestutil.AssertStringsEqual(t, x.Id(), "ok", "Expected id \"ok\". Actual: %s.", x.Id())
+	gotestutil.AssertNil(t, page.QueryOne("#dummy"), "Expected nil element for \"#dummy\".")
+}
@@ -0,0 +1,259 @@
+package goui
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"log"
+	"strings"
+)
+
+// Native HTML rendering of an element tree, for pages that don't need a user template.
+//
+
+// Describes how a content type is written as an HTML tag.
+type htmlTag struct {
+	// The HTML tag name, e.g. "a", "ul", "input"
+	name string
+	// Void elements have no closing tag and no content, e.g. <hr>, <input>
+	void bool
+	// For <input> elements, the value of the type attribute.
+	inputType string
+}
+
+var (
+	// Content type to tag mapping for the ContentType* and ContentInput* values.
+	contentTags = map[string]htmlTag{
+		ContentTypeLink:        {name: "a"},
+		ContentTypeMenu:        {name: "ul"},
+		ContentTypeImage:       {name: "img", void: true},
+		ContentTypeSeparator:   {name: "hr", void: true},
+		ContentTypeForm:        {name: "form"},
+		ContentTypeFieldError:  {name: "span"},
+		ContentTypeSelect:      {name: "select"},
+		ContentTypeOption:      {name: "option"},
+		ContentTypeOptGroup:    {name: "optgroup"},
+		ContentTypeTextarea:    {name: "textarea"},
+		ContentTypeDatalist:    {name: "datalist"},
+		ContentTypeBreadcrumbs: {name: "nav"},
+		ContentTypeJSONLD:      {name: "script"},
+		ContentTypePicture:     {name: "picture"},
+		ContentTypeSource:      {name: "source", void: true},
+
+		ContentInputButton:      {name: "input", void: true, inputType: "button"},
+		ContentInputCheckbox:    {name: "input", void: true, inputType: "checkbox"},
+		ContentInputColor:       {name: "input", void: true, inputType: "color"},
+		ContentInputDate:        {name: "input", void: true, inputType: "date"},
+		ContentInputDateTimeLoc: {name: "input", void: true, inputType: "datetime-local"},
+		ContentInputEmail:       {name: "input", void: true, inputType: "email"},
+		ContentInputFile:        {name: "input", void: true, inputType: "file"},
+		ContentInputHidden:      {name: "input", void: true, inputType: "hidden"},
+		ContentInputImage:       {name: "input", void: true, inputType: "image"},
+		ContentInputMonth:       {name: "input", void: true, inputType: "month"},
+		ContentInputNumber:      {name: "input", void: true, inputType: "number"},
+		ContentInputPassword:    {name: "input", void: true, inputType: "password"},
+		ContentInputRadio:       {name: "input", void: true, inputType: "radio"},
+		ContentInputRange:       {name: "input", void: true, inputType: "range"},
+		ContentInputReset:       {name: "input", void: true, inputType: "reset"},
+		ContentInputSearch:      {name: "input", void: true, inputType: "search"},
+		ContentInputSubmit:      {name: "input", void: true, inputType: "submit"},
+		ContentInputTel:         {name: "input", void: true, inputType: "tel"},
+		ContentInputText:        {name: "input", void: true, inputType: "text"},
+		ContentInputTime:        {name: "input", void: true, inputType: "time"},
+		ContentInputUrl:         {name: "input", void: true, inputType: "url"},
+		ContentInputWeek:        {name: "input", void: true, inputType: "week"},
+	}
+
+	// Standard HTML elements. A content type that is one of these names is rendered as that tag.
+	// The value is true for void elements.
+	htmlElements = map[string]bool{
+		"a": false, "abbr": false, "address": false, "area": true, "article": false, "aside": false,
+		"b": false, "base": true, "blockquote": false, "body": false, "br": true, "button": false,
+		"caption": false, "code": false, "col": true, "colgroup": false, "datalist": false, "dd": false,
+		"details": false, "dialog": false, "div": false, "dl": false, "dt": false, "em": false,
+		"embed": true, "fieldset": false, "figcaption": false, "figure": false, "footer": false,
+		"form": false, "h1": false, "h2": false, "h3": false, "h4": false, "h5": false, "h6": false,
+		"head": false, "header": false, "hr": true, "i": false, "iframe": false, "img": true,
+		"input": true, "label": false, "legend": false, "li": false, "link": true, "main": false,
+		"meta": true, "nav": false, "ol": false, "optgroup": false, "option": false, "p": false,
+		"param": true, "picture": false, "pre": false, "script": false, "section": false,
+		"select": false, "small": false, "source": true, "span": false, "strong": false,
+		"summary": false, "table": false, "tbody": false, "td": false, "textarea": false,
+		"tfoot": false, "th": false, "thead": false, "tr": false, "track": true, "ul": false,
+		"wbr": true,
+	}
+)
+
+// Used to render an element with an unknown content type.
+const defaultTag = "div"
+
+// Determine the HTML tag for a content type.
+// ContentType* and ContentInput* values are mapped to their tags. If the content type is a standard
+// HTML element name, e.g. "div" or "span", it is used as is. Any other content type renders as a <div>.
+func tagForContentType(ct string) htmlTag {
+	if t, ok := contentTags[ct]; ok {
+		return t
+	}
+	if void, ok := htmlElements[ct]; ok {
+		return htmlTag{name: ct, void: void}
+	}
+	return htmlTag{name: defaultTag}
+}
+
+// Captures the first write error so the rendering code doesn't check every write.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) write(s string) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = io.WriteString(ew.w, s)
+}
+
+// Write the element, and all children in order, as HTML.
+// The tag is selected by content type: ContentTypeLink is <a>, ContentTypeMenu is <ul> with each child
+// in an <li>, ContentTypeSeparator is <hr>, ContentInput* values are <input type="...">, etc.
+// Text is escaped and written as the element content, except for ContentTypeJSONLD, where the text
+// is JSON in a <script type="application/ld+json">, whatever the type attribute. For images, the text is the alt attribute, for button, submit and reset
+// inputs the text is the value attribute, and for other inputs, selects and textareas the text is a
+// <label> paired with the control. The value of a textarea is its content.
+// Implements the HTMLElementWriter interface
+func (he *UIObject) Render(w io.Writer) error {
+	ew := &errWriter{w: w}
+	he.render(ew)
+	if ew.err != nil {
+		return errorf("Error on Render.", ew.err)
+	}
+	return nil
+}
+
+// Render the element to a string. Templates can use the {{.HTML}} pipeline to include the
+// element without defining its markup. A render error is logged, and the markup ends with an HTML
+// comment marking the failure.
+func (he *UIObject) HTML() template.HTML {
+	var b bytes.Buffer
+	if err := he.Render(&b); err != nil {
+		log.Printf("goui.HTML: %s", err)
+		b.WriteString("<!-- goui: render failed -->")
+	}
+	return template.HTML(b.String())
+}
+
+func (he *UIObject) render(ew *errWriter) {
+	tag := tagForContentType(he.contentType)
+	if len(he.text) > 0 && inputHasLabel(he.contentType) {
+		he.renderLabeled(ew, tag)
+		return
+	}
+	he.renderElement(ew, tag)
+}
+
+// Write an input with its text as a paired <label for=...>. The label follows checkboxes and radio
+// buttons, and precedes other inputs. An input without an id is wrapped in the label instead.
+func (he *UIObject) renderLabeled(ew *errWriter, tag htmlTag) {
+	text := template.HTMLEscapeString(he.text)
+	if len(he.id) == 0 || he.hideId {
+		ew.write("<label>" + text + " ")
+		he.renderElement(ew, tag)
+		ew.write("</label>")
+		return
+	}
+	label := "<label for=\"" + template.HTMLEscapeString(he.id) + "\">" + text + "</label>"
+	if he.contentType == ContentInputCheckbox || he.contentType == ContentInputRadio {
+		he.renderElement(ew, tag)
+		ew.write(label)
+		return
+	}
+	ew.write(label)
+	he.renderElement(ew, tag)
+}
+
+func (he *UIObject) renderElement(ew *errWriter, tag htmlTag) {
+	ew.write("<" + tag.name)
+	skip := []string{"id", "class"}
+	if tag.name == "textarea" {
+		skip = append(skip, "value")
+	}
+	if len(tag.inputType) > 0 {
+		writeAttribute(ew, "type", tag.inputType)
+		skip = append(skip, "type")
+	}
+	if he.contentType == ContentTypeJSONLD {
+		// The script type is what makes the text data, and not a script to run.
+		writeAttribute(ew, "type", "application/ld+json")
+		skip = append(skip, "type")
+	}
+	if len(he.id) > 0 && !he.hideId {
+		writeAttribute(ew, "id", he.id)
+	}
+	if len(he.class) > 0 {
+		writeAttribute(ew, "class", he.class)
+	}
+	he.renderTextAttribute(ew, tag)
+	he.attrs.render(ew, skip...)
+	ew.write(">")
+
+	if tag.void {
+		return
+	}
+	switch {
+	case tag.name == "textarea":
+		// A newline right after the tag is dropped by the browser, so keep a leading newline.
+		if v := he.attrs["value"]; len(v) > 0 && v[0] == '\n' {
+			ew.write("\n")
+		}
+		ew.write(template.HTMLEscapeString(he.attrs["value"]))
+	case he.contentType == ContentTypeJSONLD:
+		// JSON is not HTML escaped in a script. A "<" can only be in a JSON string, where the
+		// escaped form is the same string, so the script can't be ended early.
+		ew.write(strings.Replace(he.text, "<", `\u003c`, -1))
+	case !inputHasLabel(he.contentType):
+		ew.write(template.HTMLEscapeString(he.text))
+	}
+	for _, c := range he.ChildrenByOrder() {
+		if tag.name == "ul" || tag.name == "ol" {
+			ew.write("<li>")
+			c.(*UIObject).renderListItem(ew)
+			ew.write("</li>")
+			continue
+		}
+		c.(*UIObject).render(ew)
+	}
+	ew.write("</" + tag.name + ">")
+}
+
+// Write a child of a list. A nested list with text is a submenu, and the text is written as a
+// caption before the list, e.g. <li><span>Products</span><ul>...</ul></li>.
+func (he *UIObject) renderListItem(ew *errWriter) {
+	tag := tagForContentType(he.contentType)
+	if len(he.text) == 0 || (tag.name != "ul" && tag.name != "ol") {
+		he.render(ew)
+		return
+	}
+	ew.write("<span>" + template.HTMLEscapeString(he.text) + "</span>")
+	sub := *he
+	sub.text = ""
+	sub.renderElement(ew, tag)
+}
+
+// Void elements have no content, so the text is written to the attribute that displays it.
+func (he *UIObject) renderTextAttribute(ew *errWriter, tag htmlTag) {
+	if len(he.text) == 0 {
+		return
+	}
+	var attName string
+	switch {
+	case tag.name == "img":
+		attName = "alt"
+	case tag.inputType == "button" || tag.inputType == "submit" || tag.inputType == "reset":
+		attName = "value"
+	default:
+		return
+	}
+	if _, ok := he.attrs[attName]; !ok {
+		writeAttribute(ew, attName, he.text)
+	}
+}
@@ -0,0 +1,70 @@
+package goui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func renderString(t *testing.T, uio *UIObject) string {
+	var b bytes.Buffer
+	if err := uio.Render(&b); err != nil {
+		t.Fatalf("Error on Render: %s.\n", err)
+	}
+	return b.String()
+}
+
+func TestUIObject_Render(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		link := NewElement(ContentTypeLink, "home", "nav-link", "Home")
+		link.AddAttribute("href", "/")
+		x := renderString(t, link)
+		gotestutil.AssertStringsEqual(t, x, `<a id="home" class="nav-link" href="/">Home</a>`,
+			"Unexpected link HTML. Actual: %s.", x)
+	})
+
+	t.Run("A2", func(t *testing.T) {
+		menu := NewElement(ContentTypeMenu, "nav", "", "")
+		menu.AddChild(NewElement(ContentTypeLink, "m1", "", "One"))
+		menu.AddChild(NewElement(ContentTypeSeparator, "sep1", "", ""))
+		x := renderString(t, menu)
+		gotestutil.AssertStringsEqual(t, x,
+			`<ul id="nav"><li><a id="m1">One</a></li><li><hr id="sep1"></li></ul>`,
+			"Unexpected menu HTML. Actual: %s.", x)
+	})
+
+	t.Run("A3", func(t *testing.T) {
+		x := renderString(t, NewElement(ContentInputSubmit, "save", "btn", "Save"))
+		gotestutil.AssertStringsEqual(t, x, `<input type="submit" id="save" class="btn" value="Save">`,
+			"Unexpected submit HTML. Actual: %s.", x)
+	})
+
+	t.Run("A4", func(t *testing.T) {
+		x := renderString(t, NewElement("span", "s1", "", "a"))
+		gotestutil.AssertStringsEqual(t, x, `<span id="s1">a</span>`, "Unexpected span HTML. Actual: %s.", x)
+	})
+
+	t.Run("B1", func(t *testing.T) {
+		x := renderString(t, NewElement("panel", "p1", "", "<b>"))
+		gotestutil.AssertStringsEqual(t, x, `<div id="p1">&lt;b&gt;</div>`,
+			"Expected escaped text in a div. Actual: %s.", x)
+	})
+}
+
+func TestRenderJSONLD(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		el := NewElement(ContentTypeJSONLD, "ld", "", `{"name":"</script><b>"}`)
+		x := renderString(t, el)
+		gotestutil.AssertStringsEqual(t, x, `<script type="application/ld+json" id="ld">{"name":"\u003c/script>\u003cb>"}</script>`,
+			"Unexpected JSON-LD HTML. Actual: %s.", x)
+	})
+	t.Run("B1", func(t *testing.T) {
+		// A caller's type can't make the JSON a script that runs.
+		el := NewElement(ContentTypeJSONLD, "ld", "", `{}`)
+		el.AddAttribute("type", "text/javascript")
+		x := renderString(t, el)
+		gotestutil.AssertStringsEqual(t, x, `<script type="application/ld+json" id="ld">{}</script>`,
+			"Expected the JSON-LD type. Actual: %s.", x)
+	})
+}
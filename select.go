@@ -0,0 +1,318 @@
+package goui
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Builders for <select>, <option>, <optgroup>, <textarea> and <datalist> elements. As with the
+// input builders, the text of a select or textarea is its <label>, and Form.Bind sets the
+// submitted values.
+//
+// Example:
+//     sizes := NewSelect("size").Label("Size").AddOptions(OptionsFromSlice([]string{"S", "M", "L"}, nil))
+//     sizes.Select("M")
+
+// A <select> element. The options are its children, or the children of its option groups.
+type SelectElement struct {
+	*UIObject
+}
+
+// Create a select. The id is derived from the name, as for inputs.
+func NewSelect(name string) *SelectElement {
+	return GetUIConfig().NewSelect(name)
+}
+
+// Create a select, as NewSelect, with the context IdGenerator for the ids of its options.
+func (uic *UIContext) NewSelect(name string) *SelectElement {
+	s := &SelectElement{uic.NewElement(ContentTypeSelect, SelectorSafeId(name), "", "")}
+	if len(name) > 0 {
+		s.attrs["name"] = name
+	}
+	return s
+}
+
+// Set the text of the <label> paired with the select.
+func (s *SelectElement) Label(text string) *SelectElement {
+	s.SetText(text)
+	return s
+}
+
+// Allow several options to be selected.
+func (s *SelectElement) Multiple() *SelectElement {
+	setBoolAttribute(s.UIObject, "multiple", true)
+	return s
+}
+
+// Set or clear the required attribute.
+func (s *SelectElement) Required(on bool) *SelectElement {
+	setBoolAttribute(s.UIObject, "required", on)
+	return s
+}
+
+// Set or clear the disabled attribute.
+func (s *SelectElement) Disabled(on bool) *SelectElement {
+	setBoolAttribute(s.UIObject, "disabled", on)
+	return s
+}
+
+// Add an option.
+func (s *SelectElement) Option(value, label string) *SelectElement {
+	s.AddChild(NewOption(value, label))
+	return s
+}
+
+// Add options, e.g. from OptionsFromSlice or OptionsFromMap.
+func (s *SelectElement) AddOptions(opts []*OptionElement) *SelectElement {
+	for _, o := range opts {
+		s.AddChild(o)
+	}
+	return s
+}
+
+// Add an option group.
+func (s *SelectElement) AddGroup(g *OptGroupElement) *SelectElement {
+	s.AddChild(g)
+	return s
+}
+
+// Return the options, including the options in groups, in order.
+func (s *SelectElement) Options() []*OptionElement {
+	var x []*OptionElement
+	s.walk(func(uio *UIObject, depth int) WalkAction {
+		if uio.contentType == ContentTypeOption {
+			x = append(x, &OptionElement{uio})
+		}
+		return WalkContinue
+	}, 0)
+	return x
+}
+
+// Select the options with the values, and clear the other options. Without the multiple attribute,
+// only the first matching option is selected.
+func (s *SelectElement) Select(values ...string) *SelectElement {
+	_, multiple := s.attrs["multiple"]
+	found := false
+	for _, o := range s.Options() {
+		on := containsString(values, o.Value()) && (multiple || !found)
+		found = found || on
+		o.Selected(on)
+	}
+	return s
+}
+
+// Return the values of the selected options.
+func (s *SelectElement) Selected() []string {
+	var x []string
+	for _, o := range s.Options() {
+		if _, ok := o.attrs["selected"]; ok {
+			x = append(x, o.Value())
+		}
+	}
+	return x
+}
+
+// An <option> of a select or datalist. The text is the label.
+type OptionElement struct {
+	*UIObject
+}
+
+// Create an option. The id is generated when it is added to a select.
+func NewOption(value, label string) *OptionElement {
+	o := &OptionElement{GetUIConfig().NewElement(ContentTypeOption, "", "", label)}
+	o.attrs["value"] = value
+	return o
+}
+
+// Return the submitted value: the value attribute, or the label if there is no value.
+func (o *OptionElement) Value() string {
+	return fieldValue(o.UIObject, o.text)
+}
+
+// Set or clear the selected attribute.
+func (o *OptionElement) Selected(on bool) *OptionElement {
+	setBoolAttribute(o.UIObject, "selected", on)
+	return o
+}
+
+// Set or clear the disabled attribute.
+func (o *OptionElement) Disabled(on bool) *OptionElement {
+	setBoolAttribute(o.UIObject, "disabled", on)
+	return o
+}
+
+// An <optgroup> of a select.
+type OptGroupElement struct {
+	*UIObject
+}
+
+// Create an option group with the label.
+func NewOptGroup(label string) *OptGroupElement {
+	return GetUIConfig().NewOptGroup(label)
+}
+
+// Create an option group, as NewOptGroup, with the context IdGenerator for the ids of its options.
+func (uic *UIContext) NewOptGroup(label string) *OptGroupElement {
+	g := &OptGroupElement{uic.NewElement(ContentTypeOptGroup, "", "", "")}
+	g.attrs["label"] = label
+	return g
+}
+
+// Add an option.
+func (g *OptGroupElement) Option(value, label string) *OptGroupElement {
+	g.AddChild(NewOption(value, label))
+	return g
+}
+
+// Add options, e.g. from OptionsFromSlice or OptionsFromMap.
+func (g *OptGroupElement) AddOptions(opts []*OptionElement) *OptGroupElement {
+	for _, o := range opts {
+		g.AddChild(o)
+	}
+	return g
+}
+
+// Set or clear the disabled attribute, which disables all options of the group.
+func (g *OptGroupElement) Disabled(on bool) *OptGroupElement {
+	setBoolAttribute(g.UIObject, "disabled", on)
+	return g
+}
+
+// Create options from the elements of a slice or array, in order. The value of an option is the
+// element, formatted with fmt.Sprint. The label function returns the label of an element; if it is
+// nil, the label is the value. A value that is not a slice or array is logged, and returns no options.
+func OptionsFromSlice(items interface{}, label func(v interface{}) string) []*OptionElement {
+	rv := reflect.ValueOf(items)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		log.Printf("goui.OptionsFromSlice: %T is not a slice", items)
+		return nil
+	}
+	x := make([]*OptionElement, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		v := rv.Index(i).Interface()
+		l := fmt.Sprint(v)
+		if label != nil {
+			l = label(v)
+		}
+		x = append(x, NewOption(fmt.Sprint(v), l))
+	}
+	return x
+}
+
+// Create options from the entries of a map, in label order. The value of an option is the key,
+// formatted with fmt.Sprint. The label function returns the label of an entry; if it is nil, the
+// label is the map value, formatted with fmt.Sprint. A value that is not a map is logged, and
+// returns no options.
+func OptionsFromMap(m interface{}, label func(k, v interface{}) string) []*OptionElement {
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Map {
+		log.Printf("goui.OptionsFromMap: %T is not a map", m)
+		return nil
+	}
+	x := make([]*OptionElement, 0, rv.Len())
+	for _, k := range rv.MapKeys() {
+		v := rv.MapIndex(k).Interface()
+		l := fmt.Sprint(v)
+		if label != nil {
+			l = label(k.Interface(), v)
+		}
+		x = append(x, NewOption(fmt.Sprint(k.Interface()), l))
+	}
+	sort.Slice(x, func(i, j int) bool {
+		if x[i].text != x[j].text {
+			return x[i].text < x[j].text
+		}
+		return x[i].Value() < x[j].Value()
+	})
+	return x
+}
+
+// A <textarea> element. The text is the label, and the value is the content.
+type TextareaElement struct {
+	*UIObject
+}
+
+// Create a textarea. The id is derived from the name, as for inputs.
+func NewTextarea(name string) *TextareaElement {
+	ta := &TextareaElement{GetUIConfig().NewElement(ContentTypeTextarea, SelectorSafeId(name), "", "")}
+	if len(name) > 0 {
+		ta.attrs["name"] = name
+	}
+	return ta
+}
+
+// Set the text of the <label> paired with the textarea.
+func (ta *TextareaElement) Label(text string) *TextareaElement {
+	ta.SetText(text)
+	return ta
+}
+
+// Set the content.
+func (ta *TextareaElement) Value(v string) *TextareaElement {
+	ta.attrs["value"] = v
+	return ta
+}
+
+// Set the number of visible text lines.
+func (ta *TextareaElement) Rows(n int) *TextareaElement {
+	ta.AddAttribute("rows", strconv.Itoa(n))
+	return ta
+}
+
+// Set the visible width, in characters.
+func (ta *TextareaElement) Cols(n int) *TextareaElement {
+	ta.AddAttribute("cols", strconv.Itoa(n))
+	return ta
+}
+
+// Set the placeholder text.
+func (ta *TextareaElement) Placeholder(s string) *TextareaElement {
+	ta.AddAttribute("placeholder", s)
+	return ta
+}
+
+// Set or clear the required attribute.
+func (ta *TextareaElement) Required(on bool) *TextareaElement {
+	setBoolAttribute(ta.UIObject, "required", on)
+	return ta
+}
+
+// Set or clear the disabled attribute.
+func (ta *TextareaElement) Disabled(on bool) *TextareaElement {
+	setBoolAttribute(ta.UIObject, "disabled", on)
+	return ta
+}
+
+// A <datalist> of suggested values for an input. Set the list of an input to the datalist id.
+type DatalistElement struct {
+	*UIObject
+}
+
+// Create a datalist with an option for each value.
+func NewDatalist(id string, values ...string) *DatalistElement {
+	return GetUIConfig().NewDatalist(id, values...)
+}
+
+// Create a datalist, as NewDatalist, with the context IdGenerator for the ids of its options.
+func (uic *UIContext) NewDatalist(id string, values ...string) *DatalistElement {
+	dl := &DatalistElement{uic.NewElement(ContentTypeDatalist, id, "", "")}
+	dl.AddOptions(OptionsFromSlice(values, func(interface{}) string { return "" }))
+	return dl
+}
+
+// Add options, e.g. from OptionsFromSlice or OptionsFromMap.
+func (dl *DatalistElement) AddOptions(opts []*OptionElement) *DatalistElement {
+	for _, o := range opts {
+		dl.AddChild(o)
+	}
+	return dl
+}
+
+// Suggest the values of a datalist, by its id, for the input.
+func (in *InputElement) List(id string) *InputElement {
+	in.AddAttribute("list", id)
+	return in
+}
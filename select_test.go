@@ -0,0 +1,70 @@
+package goui
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestNewSelect(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		s := NewSelect("size").Label("Size").AddOptions(OptionsFromSlice([]string{"S", "M"}, nil))
+		s.AddGroup(NewOptGroup("Large").Option("L", "Large").Option("XL", "Extra large"))
+		s.Select("M", "L")
+		x := renderString(t, s.UIObject)
+		gotestutil.AssertStringsEqual(t, x, `<label for="size">Size</label><select id="size" name="size">`+
+			`<option id="size-option-1" value="S">S</option><option id="size-option-2" selected value="M">M</option>`+
+			`<optgroup id="size-optgroup-3" label="Large"><option id="option-1" value="L">Large</option>`+
+			`<option id="option-2" value="XL">Extra large</option></optgroup></select>`,
+			"Unexpected select HTML. Actual: %s.", x)
+
+		s.Multiple().Select("M", "L")
+		gotestutil.AssertStringsEqual(t, strings.Join(s.Selected(), ","), "M,L", "Expected multiple selected.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		opts := OptionsFromMap(map[int]string{3: "Blue", 1: "Red", 2: "Green"}, func(k, v interface{}) string {
+			return strings.ToUpper(v.(string))
+		})
+		var x []string
+		for _, o := range opts {
+			x = append(x, o.Value()+"="+o.Text())
+		}
+		gotestutil.AssertStringsEqual(t, strings.Join(x, ","), "3=BLUE,2=GREEN,1=RED", "Expected options in label order.")
+		gotestutil.AssertNil(t, OptionsFromSlice("S", nil), "Expected no options from a string.")
+	})
+}
+
+func TestNewTextarea(t *testing.T) {
+	ta := NewTextarea("notes").Label("Notes").Rows(3).Value("\n<b>hi</b>")
+	x := renderString(t, ta.UIObject)
+	gotestutil.AssertStringsEqual(t, x,
+		"<label for=\"notes\">Notes</label><textarea id=\"notes\" name=\"notes\" rows=\"3\">\n\n&lt;b&gt;hi&lt;/b&gt;</textarea>",
+		"Unexpected textarea HTML. Actual: %s.", x)
+}
+
+func TestNewDatalist(t *testing.T) {
+	in := NewTextInput("city").List("cities")
+	dl := NewDatalist("cities", "Oslo", "Rome")
+	x := renderString(t, in.UIObject) + renderString(t, dl.UIObject)
+	gotestutil.AssertStringsEqual(t, x, `<input type="text" id="city" list="cities" name="city">`+
+		`<datalist id="cities"><option id="cities-option-1" value="Oslo"></option>`+
+		`<option id="cities-option-2" value="Rome"></option></datalist>`,
+		"Unexpected datalist HTML. Actual: %s.", x)
+}
+
+func TestFormBindSelect(t *testing.T) {
+	f := NewForm("order", "/order", "post")
+	f.AddChild(NewSelect("size").Multiple().AddOptions(OptionsFromSlice([]string{"S", "M", "L"}, nil)))
+	f.AddChild(NewTextarea("notes").Value("old"))
+	f.AddChild(NewTextarea("fixed").Value("keep").Disabled(true))
+
+	_, err := f.Bind(postRequest(url.Values{"size": {"S", "L"}, "notes": {"new"}}))
+	gotestutil.AssertNil(t, err, "Unexpected error on Bind. %v", err)
+	s := &SelectElement{f.GetChildById("size").(*UIObject)}
+	gotestutil.AssertStringsEqual(t, strings.Join(s.Selected(), ","), "S,L", "Expected submitted options selected.")
+	gotestutil.AssertStringsEqual(t, f.GetChildById("notes").GetAttribute("value"), "new", "Expected textarea value.")
+	gotestutil.AssertStringsEqual(t, f.GetChildById("fixed").GetAttribute("value"), "keep",
+		"Expected disabled textarea unchanged.")
+}
@@ -0,0 +1,261 @@
+package goui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Inline CSS styles. The "style" attribute of an element is the only copy of the styles, so
+// templates, JSON and Diff see the same value as the style methods.
+//
+
+var (
+	// A CSS property name is not valid.
+	ErrStyleProperty = errors.New("invalid CSS property name")
+	// A CSS value could end the declaration or rule, or run script.
+	ErrStyleValue = errors.New("unsafe CSS value")
+
+	// Standard properties, including vendor prefixes, and custom properties, e.g. --main-color
+	cssPropertyRe = regexp.MustCompile(`^(-?[a-z][a-z0-9-]*|--[a-zA-Z0-9_-]+)$`)
+	// Content that isn't allowed anywhere in a value, after CSS escapes are decoded. Quotes are
+	// escaped with the attribute.
+	cssUnsafeRe = regexp.MustCompile(`(?i)[<>]|expression\s*\(|javascript:|vbscript:|@import`)
+)
+
+// The declarations of an inline style, e.g. "width: 10px; display: none", in the order they are set.
+type StyleMap struct {
+	props  []string
+	values map[string]string
+}
+
+// Parse the value of a style attribute. Declarations with an invalid property name or an unsafe
+// value are left out.
+func ParseStyle(s string) StyleMap {
+	var sm StyleMap
+	for _, decl := range splitDeclarations(s) {
+		i := strings.IndexByte(decl, ':')
+		if i < 0 {
+			continue
+		}
+		if err := sm.Set(decl[:i], decl[i+1:]); err != nil {
+			log.Printf("goui.ParseStyle: %s", err)
+		}
+	}
+	return sm
+}
+
+// Split on semicolons that are not in quotes or parentheses, e.g. url("a;b").
+func splitDeclarations(s string) []string {
+	var x []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			// An escaped character, e.g. "\;", doesn't end anything.
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == ';' && depth == 0:
+			x = append(x, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(x, s[start:])
+}
+
+// Set a property. An existing property keeps its position. Property names are lower case, except
+// for custom properties. Returns ErrStyleProperty or ErrStyleValue if the property can't be set.
+func (sm *StyleMap) Set(prop, value string) error {
+	prop, value = cssPropertyName(prop), strings.TrimSpace(value)
+	if !cssPropertyRe.MatchString(prop) {
+		return errorf(fmt.Sprintf("Style property %q", prop), ErrStyleProperty)
+	}
+	if !safeCSSValue(value) {
+		return errorf(fmt.Sprintf("Style %q value %q", prop, value), ErrStyleValue)
+	}
+	if sm.values == nil {
+		sm.values = make(map[string]string, 1)
+	}
+	if _, ok := sm.values[prop]; !ok {
+		sm.props = append(sm.props, prop)
+	}
+	sm.values[prop] = value
+	return nil
+}
+
+// A value is safe if it can't end the declaration or the rule, or run script. Semicolons are allowed
+// in quotes, e.g. url("a;b.png"), but braces and unbalanced quotes or parentheses are not. CSS
+// escapes, e.g. content: "\201C", are allowed, and are decoded before the value is checked, so they
+// can't hide "expression(" or "javascript:".
+func safeCSSValue(v string) bool {
+	if len(v) == 0 || cssUnsafeRe.MatchString(v) || cssUnsafeRe.MatchString(cssUnescape(v)) {
+		return false
+	}
+	var quote byte
+	depth := 0
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; {
+		case c == '\\':
+			// A backslash at the end, or before a newline, would escape what follows the value.
+			if i++; i == len(v) || v[i] == '\n' || v[i] == '\r' || v[i] == '\f' {
+				return false
+			}
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth--; depth < 0 {
+				return false
+			}
+		case c == ';' || c == '{' || c == '}':
+			return false
+		}
+	}
+	return quote == 0 && depth == 0
+}
+
+// Decode the CSS escapes of a value: a backslash and 1 to 6 hex digits, with an optional space,
+// or a backslash and any other character.
+func cssUnescape(v string) string {
+	if strings.IndexByte(v, '\\') < 0 {
+		return v
+	}
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		if v[i] != '\\' || i+1 == len(v) {
+			b.WriteByte(v[i])
+			continue
+		}
+		j := i + 1
+		for j < len(v) && j < i+7 && isHexDigit(v[j]) {
+			j++
+		}
+		if j == i+1 {
+			b.WriteByte(v[j])
+			i = j
+			continue
+		}
+		n, _ := strconv.ParseUint(v[i+1:j], 16, 32)
+		b.WriteRune(rune(n))
+		if j < len(v) && (v[j] == ' ' || v[j] == '\t' || v[j] == '\n') {
+			j++
+		}
+		i = j - 1
+	}
+	return b.String()
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// Normalize a property name: lower case, except for custom properties, which are case sensitive.
+func cssPropertyName(prop string) string {
+	prop = strings.TrimSpace(prop)
+	if !strings.HasPrefix(prop, "--") {
+		prop = strings.ToLower(prop)
+	}
+	return prop
+}
+
+// Return the value of a property, or "" if it isn't set.
+func (sm StyleMap) Get(prop string) string {
+	return sm.values[cssPropertyName(prop)]
+}
+
+// Remove a property.
+func (sm *StyleMap) Remove(prop string) {
+	prop = cssPropertyName(prop)
+	if _, ok := sm.values[prop]; !ok {
+		return
+	}
+	delete(sm.values, prop)
+	var x []string
+	for _, p := range sm.props {
+		if p != prop {
+			x = append(x, p)
+		}
+	}
+	sm.props = x
+}
+
+// Return the property names, in order.
+func (sm StyleMap) Properties() []string {
+	return append([]string(nil), sm.props...)
+}
+
+// Return the number of properties.
+func (sm StyleMap) Len() int {
+	return len(sm.props)
+}
+
+// Render the declarations for a style attribute, e.g. "width: 10px; display: none"
+func (sm StyleMap) String() string {
+	x := make([]string, 0, len(sm.props))
+	for _, p := range sm.props {
+		x = append(x, p+": "+sm.values[p])
+	}
+	return strings.Join(x, "; ")
+}
+
+// Return the inline styles of the object.
+// Implements the Style interface
+func (he *UIObject) StyleMap() StyleMap {
+	return ParseStyle(he.attrs["style"])
+}
+
+// Set the styles of the object, replacing any existing styles.
+// Implements the Style interface
+func (he *UIObject) SetStyleMap(sm StyleMap) HTMLElementWriter {
+	if sm.Len() == 0 {
+		delete(he.attrs, "style")
+		return he
+	}
+	he.attrs["style"] = sm.String()
+	return he
+}
+
+// Set a single CSS property in the style attribute, e.g. SetStyle("width", "50%").
+// Other properties are unchanged. An invalid property name or unsafe value is logged and not set.
+// Implements the Style interface
+func (he *UIObject) SetStyle(prop, value string) HTMLElementWriter {
+	sm := he.StyleMap()
+	if err := sm.Set(prop, value); err != nil {
+		log.Printf("goui.SetStyle: %s", err)
+		return he
+	}
+	return he.SetStyleMap(sm)
+}
+
+// Remove a single CSS property from the style attribute.
+// Implements the Style interface
+func (he *UIObject) RemoveStyle(prop string) HTMLElementWriter {
+	sm := he.StyleMap()
+	sm.Remove(prop)
+	return he.SetStyleMap(sm)
+}
+
+// Return the value of a CSS property in the style attribute, or "" if it isn't set.
+// Templates access the value with the {{.Style "width"}} pipeline.
+// Implements the Style interface
+func (he *UIObject) Style(prop string) string {
+	return he.StyleMap().Get(prop)
+}
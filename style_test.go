@@ -0,0 +1,60 @@
+package goui
+
+import (
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestParseStyle(t *testing.T) {
+	sm := ParseStyle(`width: 10px; background: url("a;b.png") no-repeat; bad prop: x; color: red;`)
+	gotestutil.AssertEqual(t, sm.Properties(), []string{"width", "background", "color"},
+		"Unexpected properties. Actual: %v.", sm.Properties())
+	gotestutil.AssertStringsEqual(t, sm.Get("background"), `url("a;b.png") no-repeat`,
+		"Unexpected background. Actual: %s.", sm.Get("background"))
+}
+
+func TestStyleMap_Set(t *testing.T) {
+	var sm StyleMap
+	gotestutil.AssertNil(t, sm.Set("Width", "10px"), "Expected nil error for width.")
+	gotestutil.AssertNil(t, sm.Set("--main-Color", "#fff"), "Expected nil error for custom property.")
+	for _, v := range []string{"red; position: fixed", "expression(alert(1))", "url(javascript:x)", "</style>", "",
+		`e\78 pression(alert(1))`, `url(java\73 cript:x)`, `\3c /style>`, `"a" \`} {
+		gotestutil.AssertNotNil(t, sm.Set("color", v), "Expected error for value %q.", v)
+	}
+	for _, v := range []string{`"\201C"`, `"a\"b;c"`, `"\\"`} {
+		gotestutil.AssertNil(t, sm.Set("content", v), "Expected nil error for value %q.", v)
+	}
+	gotestutil.AssertStringsEqual(t, ParseStyle(`content: "a\"b;c"; color: red`).Get("color"), "red",
+		"Expected an escaped quote kept in the string.")
+	sm.Remove("content")
+	gotestutil.AssertNotNil(t, sm.Set("col or", "red"), "Expected error for property \"col or\".")
+	gotestutil.AssertStringsEqual(t, sm.String(), "width: 10px; --main-Color: #fff", "Unexpected style. Actual: %s.",
+		sm.String())
+	gotestutil.AssertStringsEqual(t, sm.Get("WIDTH"), "10px", "Expected a case-insensitive property name.")
+	gotestutil.AssertEmptyString(t, sm.Get("--main-color"), "Expected a case-sensitive custom property.")
+	sm.Remove(" Width")
+	gotestutil.AssertStringsEqual(t, sm.String(), "--main-Color: #fff", "Expected width removed.")
+
+	uio := NewElement("div", "box", "", "")
+	uio.SetStyle("Width", "1px")
+	gotestutil.AssertStringsEqual(t, uio.Style("Width"), "1px", "Expected Style to ignore case.")
+}
+
+func TestUIObject_SetStyle(t *testing.T) {
+	uio := NewElement("div", "chart1", "", "")
+	uio.SetStyle("width", "400px").SetStyle("display", "none").SetStyle("width", "50%")
+	gotestutil.AssertStringsEqual(t, uio.GetAttribute("style"), "width: 50%; display: none",
+		"Unexpected style attribute. Actual: %s.", uio.GetAttribute("style"))
+	gotestutil.AssertStringsEqual(t, uio.Style("display"), "none", "Expected display none.")
+
+	uio.SetStyle("display", "block;color:red")
+	gotestutil.AssertStringsEqual(t, uio.Style("display"), "none", "Expected unsafe value not set.")
+
+	uio.RemoveStyle("width")
+	gotestutil.AssertStringsEqual(t, uio.GetAttribute("style"), "display: none",
+		"Unexpected style attribute. Actual: %s.", uio.GetAttribute("style"))
+	uio.RemoveStyle("display")
+	_, found := uio.attrs["style"]
+	gotestutil.AssertFalse(t, found, "Expected no style attribute.")
+}
@@ -0,0 +1,437 @@
+package goui
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Themes map semantic roles, e.g. a primary button or the active navigation link, to the CSS
+// classes of a framework, so code doesn't hard-code "btn-primary". The default theme is selected by
+// name with the "theme" config key. UIContext.SetTheme selects the theme of the elements created
+// by a context, and their descendants.
+//
+// Example:
+//     uic := NewUIContext().SetTheme("bulma")
+//     btn := uic.NewElement("button", "save", "", "Save")
+//     btn.AddThemeClass(ThemeButtonPrimary)   // class="button is-primary"
+//
+// In templates, with the default theme: <button class="{{ThemeClass "button-primary"}}">
+
+// A semantic role of an element.
+type ThemeRole string
+
+const (
+	ThemeButton          ThemeRole = "button"
+	ThemeButtonPrimary   ThemeRole = "button-primary"
+	ThemeButtonSecondary ThemeRole = "button-secondary"
+	ThemeButtonDanger    ThemeRole = "button-danger"
+
+	ThemeAlertInfo    ThemeRole = "alert-info"
+	ThemeAlertSuccess ThemeRole = "alert-success"
+	ThemeAlertWarning ThemeRole = "alert-warning"
+	ThemeAlertDanger  ThemeRole = "alert-danger"
+	ThemeAlertDismiss ThemeRole = "alert-dismiss"
+
+	ThemeFormGroup    ThemeRole = "form-group"
+	ThemeFormLabel    ThemeRole = "form-label"
+	ThemeFormControl  ThemeRole = "form-control"
+	ThemeFormSelect   ThemeRole = "form-select"
+	ThemeFormCheck    ThemeRole = "form-check"
+	ThemeFieldInvalid ThemeRole = "field-invalid"
+	ThemeFieldError   ThemeRole = "field-error"
+
+	ThemeNav       ThemeRole = "nav"
+	ThemeNavLink   ThemeRole = "nav-link"
+	ThemeNavActive ThemeRole = "nav-active"
+
+	ThemeTable      ThemeRole = "table"
+	ThemePagination ThemeRole = "pagination"
+	ThemePageLink   ThemeRole = "page-link"
+	ThemePageActive ThemeRole = "page-active"
+
+	ThemeContainer ThemeRole = "container"
+	ThemeRow       ThemeRole = "row"
+
+	ThemeCard       ThemeRole = "card"
+	ThemeCardHeader ThemeRole = "card-header"
+	ThemeCardBody   ThemeRole = "card-body"
+	ThemeCardFooter ThemeRole = "card-footer"
+
+	ThemeTabs      ThemeRole = "tabs"
+	ThemeTab       ThemeRole = "tab"
+	ThemeTabActive ThemeRole = "tab-active"
+	ThemeTabPanel  ThemeRole = "tab-panel"
+
+	ThemeModal        ThemeRole = "modal"
+	ThemeModalDialog  ThemeRole = "modal-dialog"
+	ThemeModalContent ThemeRole = "modal-content"
+	ThemeModalHeader  ThemeRole = "modal-header"
+	ThemeModalTitle   ThemeRole = "modal-title"
+	ThemeModalBody    ThemeRole = "modal-body"
+	ThemeModalFooter  ThemeRole = "modal-footer"
+	ThemeModalHidden  ThemeRole = "modal-hidden"
+	ThemeModalShown   ThemeRole = "modal-shown"
+
+	ThemeAccordion       ThemeRole = "accordion"
+	ThemeAccordionItem   ThemeRole = "accordion-item"
+	ThemeAccordionButton ThemeRole = "accordion-button"
+	ThemeAccordionBody   ThemeRole = "accordion-body"
+)
+
+// The default theme name.
+const DefaultTheme = "bootstrap5"
+
+// Maps semantic roles to the CSS classes of a framework.
+type Theme interface {
+	// The theme name, used in the "theme" config key.
+	Name() string
+	// The classes for a role, or nil if the theme has none.
+	Classes(role ThemeRole) []string
+	// The classes of a grid column that spans a number of the 12 columns of a row.
+	Column(span int) []string
+}
+
+// A Theme defined by a table of classes.
+type ClassTheme struct {
+	name    string
+	classes map[ThemeRole]string
+	column  string
+}
+
+// Create a theme. The classes of a role are separated by spaces. In the column classes, "{n}" is
+// replaced by the number of columns, e.g. "col-md-{n}".
+func NewClassTheme(name string, classes map[ThemeRole]string, column string) *ClassTheme {
+	return &ClassTheme{name: name, classes: classes, column: column}
+}
+
+func (ct *ClassTheme) Name() string {
+	return ct.name
+}
+
+func (ct *ClassTheme) Classes(role ThemeRole) []string {
+	return strings.Fields(ct.classes[role])
+}
+
+func (ct *ClassTheme) Column(span int) []string {
+	if span < 1 || span > 12 {
+		log.Printf("goui.Column: span %d is not 1 to 12", span)
+		span = 12
+	}
+	return strings.Fields(strings.Replace(ct.column, "{n}", strconv.Itoa(span), -1))
+}
+
+var (
+	themesMu sync.RWMutex
+	themes   = map[string]Theme{}
+)
+
+func init() {
+	for _, t := range []Theme{bootstrap3Theme, bootstrap5Theme, bulmaTheme, tailwindTheme} {
+		themes[t.Name()] = t
+	}
+}
+
+// Add a theme, or replace the theme with the same name. The theme is available to all contexts.
+func (uic *UIContext) RegisterTheme(t Theme) *UIContext {
+	themesMu.Lock()
+	defer themesMu.Unlock()
+	themes[t.Name()] = t
+	return uic
+}
+
+// Return the names of the registered themes, in order.
+func (uic *UIContext) ThemeNames() []string {
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+	x := make([]string, 0, len(themes))
+	for k := range themes {
+		x = append(x, k)
+	}
+	sort.Strings(x)
+	return x
+}
+
+// Select the theme of the elements created by the context, by name. The theme only applies to this
+// context; other contexts use the "theme" config key. An unknown name is logged, and the theme is
+// unchanged.
+func (uic *UIContext) SetTheme(name string) *UIContext {
+	themesMu.RLock()
+	t, ok := themes[name]
+	themesMu.RUnlock()
+	if !ok {
+		log.Printf("goui.SetTheme: unknown theme %q", name)
+		return uic
+	}
+	uic.Lock()
+	defer uic.Unlock()
+	uic.theme = t
+	return uic
+}
+
+// Return the theme selected by SetTheme, or else by the "theme" config key. The default is
+// DefaultTheme.
+func (uic *UIContext) Theme() Theme {
+	if uic.theme != nil {
+		return uic.theme
+	}
+	name := uic.p.GetString(CfgTheme)
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+	if t, ok := themes[name]; ok {
+		return t
+	}
+	if len(name) > 0 {
+		log.Printf("goui.Theme: unknown theme %q, using %q", name, DefaultTheme)
+	}
+	return themes[DefaultTheme]
+}
+
+// Return the theme of the element: the theme of the UIContext that created it, or of the nearest
+// ancestor with one. The default is the theme of the "theme" config key.
+func (he *UIObject) currentTheme() Theme {
+	for p := he; p != nil; p = p.parent {
+		if p.theme != nil {
+			return p.theme
+		}
+	}
+	return defaultCfg.Theme()
+}
+
+// The classes for a role in the default theme, separated by spaces. Templates call this as
+// {{ThemeClass "button-primary"}}.
+func ThemeClass(role string) string {
+	return strings.Join(defaultCfg.Theme().Classes(ThemeRole(role)), " ")
+}
+
+// Add the classes for the roles in the theme of the element.
+// Implements the Class interface
+func (he *UIObject) AddThemeClass(roles ...ThemeRole) HTMLElementWriter {
+	t := he.currentTheme()
+	x := he.ClassList()
+	for _, r := range roles {
+		x = append(x, t.Classes(r)...)
+		if !containsRole(he.themeRoles, r) {
+			he.themeRoles = append(he.themeRoles, r)
+		}
+	}
+	return he.SetClasses(x...)
+}
+
+// Remove the classes for the roles in the theme of the element. A class that is also a class of another
+// role added to the element, e.g. "text-white" of a primary button and the active nav link, is kept.
+// Implements the Class interface
+func (he *UIObject) RemoveThemeClass(roles ...ThemeRole) HTMLElementWriter {
+	var keep []ThemeRole
+	for _, r := range he.themeRoles {
+		if !containsRole(roles, r) {
+			keep = append(keep, r)
+		}
+	}
+	he.themeRoles = keep
+	t := he.currentTheme()
+	var used []string
+	for _, r := range keep {
+		used = append(used, t.Classes(r)...)
+	}
+	for _, r := range roles {
+		for _, c := range t.Classes(r) {
+			if !containsString(used, c) {
+				he.RemoveCssClass(c)
+			}
+		}
+	}
+	return he
+}
+
+func containsRole(roles []ThemeRole, role ThemeRole) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+var bootstrap3Theme = NewClassTheme("bootstrap3", map[ThemeRole]string{
+	ThemeButton:          "btn btn-default",
+	ThemeButtonPrimary:   "btn btn-primary",
+	ThemeButtonSecondary: "btn btn-default",
+	ThemeButtonDanger:    "btn btn-danger",
+	ThemeAlertInfo:       "alert alert-info",
+	ThemeAlertSuccess:    "alert alert-success",
+	ThemeAlertWarning:    "alert alert-warning",
+	ThemeAlertDanger:     "alert alert-danger",
+	ThemeAlertDismiss:    "close",
+	ThemeFormGroup:       "form-group",
+	ThemeFormLabel:       "control-label",
+	ThemeFormControl:     "form-control",
+	ThemeFormSelect:      "form-control",
+	ThemeFormCheck:       "checkbox",
+	ThemeFieldError:      "help-block",
+	ThemeNav:             "nav navbar-nav",
+	ThemeNavActive:       "active",
+	ThemeTable:           "table table-striped",
+	ThemePagination:      "pagination",
+	ThemePageActive:      "active",
+	ThemeContainer:       "container",
+	ThemeRow:             "row",
+	ThemeCard:            "panel panel-default",
+	ThemeCardHeader:      "panel-heading",
+	ThemeCardBody:        "panel-body",
+	ThemeCardFooter:      "panel-footer",
+	ThemeTabs:            "nav nav-tabs",
+	ThemeTabActive:       "active",
+	ThemeModal:           "modal",
+	ThemeModalDialog:     "modal-dialog",
+	ThemeModalContent:    "modal-content",
+	ThemeModalHeader:     "modal-header",
+	ThemeModalTitle:      "modal-title",
+	ThemeModalBody:       "modal-body",
+	ThemeModalFooter:     "modal-footer",
+	ThemeModalShown:      "in show",
+	ThemeAccordion:       "panel-group",
+	ThemeAccordionItem:   "panel panel-default",
+	ThemeAccordionButton: "btn btn-link",
+	ThemeAccordionBody:   "panel-body",
+}, "col-md-{n}")
+
+var bootstrap5Theme = NewClassTheme("bootstrap5", map[ThemeRole]string{
+	ThemeButton:          "btn",
+	ThemeButtonPrimary:   "btn btn-primary",
+	ThemeButtonSecondary: "btn btn-secondary",
+	ThemeButtonDanger:    "btn btn-danger",
+	ThemeAlertInfo:       "alert alert-info",
+	ThemeAlertSuccess:    "alert alert-success",
+	ThemeAlertWarning:    "alert alert-warning",
+	ThemeAlertDanger:     "alert alert-danger",
+	ThemeAlertDismiss:    "btn-close",
+	ThemeFormGroup:       "mb-3",
+	ThemeFormLabel:       "form-label",
+	ThemeFormControl:     "form-control",
+	ThemeFormSelect:      "form-select",
+	ThemeFormCheck:       "form-check-input",
+	ThemeFieldInvalid:    "is-invalid",
+	ThemeFieldError:      "invalid-feedback",
+	ThemeNav:             "nav",
+	ThemeNavLink:         "nav-link",
+	ThemeNavActive:       "active",
+	ThemeTable:           "table table-striped",
+	ThemePagination:      "pagination",
+	ThemePageLink:        "page-link",
+	ThemePageActive:      "active",
+	ThemeContainer:       "container",
+	ThemeRow:             "row",
+	ThemeCard:            "card",
+	ThemeCardHeader:      "card-header",
+	ThemeCardBody:        "card-body",
+	ThemeCardFooter:      "card-footer",
+	ThemeTabs:            "nav nav-tabs",
+	ThemeTab:             "nav-link",
+	ThemeTabActive:       "active",
+	ThemeModal:           "modal",
+	ThemeModalDialog:     "modal-dialog",
+	ThemeModalContent:    "modal-content",
+	ThemeModalHeader:     "modal-header",
+	ThemeModalTitle:      "modal-title",
+	ThemeModalBody:       "modal-body",
+	ThemeModalFooter:     "modal-footer",
+	ThemeModalShown:      "show d-block",
+	ThemeAccordion:       "accordion",
+	ThemeAccordionItem:   "accordion-item",
+	ThemeAccordionButton: "accordion-button",
+	ThemeAccordionBody:   "accordion-body",
+}, "col-md-{n}")
+
+var bulmaTheme = NewClassTheme("bulma", map[ThemeRole]string{
+	ThemeButton:          "button",
+	ThemeButtonPrimary:   "button is-primary",
+	ThemeButtonSecondary: "button is-light",
+	ThemeButtonDanger:    "button is-danger",
+	ThemeAlertInfo:       "notification is-info",
+	ThemeAlertSuccess:    "notification is-success",
+	ThemeAlertWarning:    "notification is-warning",
+	ThemeAlertDanger:     "notification is-danger",
+	ThemeAlertDismiss:    "delete",
+	ThemeFormGroup:       "field",
+	ThemeFormLabel:       "label",
+	ThemeFormControl:     "input",
+	ThemeFormSelect:      "select",
+	ThemeFormCheck:       "checkbox",
+	ThemeFieldInvalid:    "is-danger",
+	ThemeFieldError:      "help is-danger",
+	ThemeNav:             "menu-list",
+	ThemeNavActive:       "is-active",
+	ThemeTable:           "table is-striped",
+	ThemePagination:      "pagination-list",
+	ThemePageLink:        "pagination-link",
+	ThemePageActive:      "is-current",
+	ThemeContainer:       "container",
+	ThemeRow:             "columns",
+	ThemeCard:            "card",
+	ThemeCardHeader:      "card-header",
+	ThemeCardBody:        "card-content",
+	ThemeCardFooter:      "card-footer",
+	ThemeTabs:            "tabs",
+	ThemeTabActive:       "is-active",
+	ThemeModal:           "modal",
+	ThemeModalContent:    "modal-card",
+	ThemeModalHeader:     "modal-card-head",
+	ThemeModalTitle:      "modal-card-title",
+	ThemeModalBody:       "modal-card-body",
+	ThemeModalFooter:     "modal-card-foot",
+	ThemeModalShown:      "is-active",
+	ThemeAccordionItem:   "card",
+	ThemeAccordionButton: "card-header-title",
+	ThemeAccordionBody:   "card-content",
+}, "column is-{n}")
+
+var tailwindTheme = NewClassTheme("tailwind", map[ThemeRole]string{
+	ThemeButton:          "px-4 py-2 rounded",
+	ThemeButtonPrimary:   "px-4 py-2 rounded bg-blue-600 text-white hover:bg-blue-700",
+	ThemeButtonSecondary: "px-4 py-2 rounded bg-gray-200 text-gray-800 hover:bg-gray-300",
+	ThemeButtonDanger:    "px-4 py-2 rounded bg-red-600 text-white hover:bg-red-700",
+	ThemeAlertInfo:       "p-4 rounded border border-blue-300 bg-blue-50 text-blue-800",
+	ThemeAlertSuccess:    "p-4 rounded border border-green-300 bg-green-50 text-green-800",
+	ThemeAlertWarning:    "p-4 rounded border border-yellow-300 bg-yellow-50 text-yellow-800",
+	ThemeAlertDanger:     "p-4 rounded border border-red-300 bg-red-50 text-red-800",
+	ThemeAlertDismiss:    "ml-auto",
+	ThemeFormGroup:       "mb-4",
+	ThemeFormLabel:       "block mb-1 text-sm font-medium",
+	ThemeFormControl:     "block w-full rounded border border-gray-300 px-3 py-2",
+	ThemeFormSelect:      "block w-full rounded border border-gray-300 px-3 py-2",
+	ThemeFormCheck:       "h-4 w-4",
+	ThemeFieldInvalid:    "border-red-500",
+	ThemeFieldError:      "mt-1 text-sm text-red-600",
+	ThemeNav:             "flex space-x-4",
+	ThemeNavLink:         "px-3 py-2 rounded",
+	ThemeNavActive:       "bg-gray-900 text-white",
+	ThemeTable:           "min-w-full divide-y divide-gray-200",
+	ThemePagination:      "flex space-x-1",
+	ThemePageLink:        "px-3 py-1 rounded border",
+	ThemePageActive:      "bg-blue-600 text-white",
+	ThemeContainer:       "container mx-auto",
+	ThemeRow:             "grid grid-cols-12 gap-4",
+	ThemeCard:            "rounded border border-gray-200 shadow-sm",
+	ThemeCardHeader:      "px-4 py-3 border-b font-semibold",
+	ThemeCardBody:        "p-4",
+	ThemeCardFooter:      "px-4 py-3 border-t",
+	ThemeTabs:            "flex border-b",
+	ThemeTab:             "px-4 py-2 -mb-px",
+	ThemeTabActive:       "border-b-2 border-blue-600 text-blue-600",
+	ThemeTabPanel:        "py-4",
+	ThemeModal:           "fixed inset-0 z-50 items-center justify-center bg-black/50",
+	ThemeModalDialog:     "w-full max-w-lg",
+	ThemeModalContent:    "rounded bg-white shadow-lg",
+	ThemeModalHeader:     "flex items-center px-4 py-3 border-b",
+	ThemeModalTitle:      "text-lg font-semibold",
+	ThemeModalBody:       "p-4",
+	ThemeModalFooter:     "flex justify-end gap-2 px-4 py-3 border-t",
+	ThemeModalHidden:     "hidden",
+	ThemeModalShown:      "flex",
+	ThemeAccordion:       "divide-y rounded border",
+	ThemeAccordionButton: "w-full px-4 py-3 text-left font-medium",
+	ThemeAccordionBody:   "px-4 py-3",
+}, "col-span-{n}")
@@ -0,0 +1,85 @@
+package goui
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestTheme(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		uic := NewUIContext()
+		gotestutil.AssertStringsEqual(t, strings.Join(uic.ThemeNames(), ","), "bootstrap3,bootstrap5,bulma,tailwind",
+			"Unexpected built-in themes.")
+		gotestutil.AssertStringsEqual(t, uic.Theme().Name(), DefaultTheme, "Expected the default theme.")
+
+		btn := NewElement("button", "save", "wide", "Save")
+		btn.AddThemeClass(ThemeButtonPrimary)
+		gotestutil.AssertStringsEqual(t, btn.Class(), "wide btn btn-primary", "Unexpected bootstrap5 classes.")
+
+		uic.SetTheme("bulma")
+		gotestutil.AssertStringsEqual(t, uic.Theme().Name(), "bulma", "Expected the bulma theme.")
+		gotestutil.AssertStringsEqual(t, GetUIConfig().Theme().Name(), DefaultTheme,
+			"Expected other contexts unchanged.")
+		btn.RemoveThemeClass(ThemeButtonPrimary)
+		gotestutil.AssertStringsEqual(t, btn.Class(), "wide", "Expected the bootstrap5 classes removed.")
+		panel := uic.NewElement("div", "panel", "", "")
+		alert := NewElement("div", "msg", "", "Saved")
+		panel.AddChild(alert)
+		alert.AddThemeClass(ThemeAlertSuccess)
+		gotestutil.AssertStringsEqual(t, alert.Class(), "notification is-success",
+			"Expected the bulma classes of the context of the parent.")
+		gotestutil.AssertStringsEqual(t, strings.Join(uic.Theme().Column(4), " "), "column is-4", "Unexpected column.")
+
+		uic.SetTheme("tailwind")
+		link := uic.NewElement(ContentTypeLink, "signup", "", "Sign up")
+		link.AddThemeClass(ThemeButtonPrimary, ThemeNavActive)
+		link.RemoveThemeClass(ThemeNavActive)
+		gotestutil.AssertStringsEqual(t, link.Class(), "px-4 py-2 rounded bg-blue-600 text-white hover:bg-blue-700",
+			"Expected the classes of the other role kept.")
+		tmpl := template.Must(template.New("t").Funcs(template.FuncMap{"ThemeClass": ThemeClass}).
+			Parse(`<nav class="{{ThemeClass "nav"}}">`))
+		var b bytes.Buffer
+		gotestutil.AssertNil(t, tmpl.Execute(&b, nil), "Unexpected template error.")
+		gotestutil.AssertStringsEqual(t, b.String(), `<nav class="nav">`, "Expected the default theme in templates.")
+	})
+	t.Run("A2", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i, name := range []string{"bootstrap3", "bulma", "tailwind", "bootstrap5"} {
+			wg.Add(1)
+			go func(i int, name string) {
+				defer wg.Done()
+				uic := NewUIContext().SetTheme(name)
+				a := uic.NewAlert(fmt.Sprint("a", i), ThemeAlertInfo, "Info")
+				gotestutil.AssertStringsEqual(t, a.Class(), strings.Join(uic.Theme().Classes(ThemeAlertInfo), " "),
+					"Expected the classes of the context theme %s.", name)
+			}(i, name)
+		}
+		wg.Wait()
+	})
+	t.Run("B1", func(t *testing.T) {
+		uic := NewUIContext()
+		uic.SetTheme("bootstrap3")
+		uic.SetTheme("nosuchtheme")
+		gotestutil.AssertStringsEqual(t, uic.Theme().Name(), "bootstrap3", "Expected an unknown theme ignored.")
+		gotestutil.AssertEqual(t, len(uic.Theme().Classes(ThemeFieldInvalid)), 0, "Expected no classes for a role.")
+		gotestutil.AssertStringsEqual(t, strings.Join(uic.Theme().Column(0), " "), "col-md-12",
+			"Expected a full width column for an invalid span.")
+
+		uic.RegisterTheme(NewClassTheme("plain", map[ThemeRole]string{ThemeNavActive: "current"}, "span{n}"))
+		uic.SetTheme("plain")
+		nav := uic.NewMenu("nav").Link("home", "Home", "/").Link("blog", "Blog", "/blog/")
+		m := nav.ForRequest(httptest.NewRequest(http.MethodGet, "/blog", nil))
+		gotestutil.AssertStringsEqual(t, m.GetChildById("blog").Class(), "active current",
+			"Expected the theme nav-active class.")
+		gotestutil.AssertStringsEqual(t, m.MarkActive(httptest.NewRequest(http.MethodGet, "/", nil)).
+			GetChildById("blog").Class(), "", "Expected the theme class cleared.")
+	})
+}
@@ -0,0 +1,248 @@
+package goui
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+)
+
+// Changes to the element hierarchy. The children map and the childOrder list are always changed together.
+//
+
+var (
+	// No child, or element, with the requested id.
+	ErrNotFound = errors.New("element not found")
+	// The parent already has a child with the same id.
+	ErrDuplicateId = errors.New("duplicate element id")
+	// A child index is outside the range of children.
+	ErrIndexRange = errors.New("child index out of range")
+	// An element can't become a descendant of itself.
+	ErrCycle = errors.New("element is an ancestor of the new parent")
+	// The element is in a subtree that a copy made with CloneForWrite shares with its original, so
+	// it can't be moved to another element of the copy.
+	ErrShared = errors.New("element is shared with a copy")
+)
+
+// Return the *UIObject for an HTMLElementWriter, including types that embed a *UIObject.
+func toUIObject(ui HTMLElementWriter) *UIObject {
+	return ui.GetHTMLElementWriter().(*UIObject)
+}
+
+// Find the list element of a child by id.
+func (he *UIObject) childElement(id string) *list.Element {
+	if _, ok := he.children[id]; !ok {
+		return nil
+	}
+	for l := he.childOrder.Front(); l != nil; l = l.Next() {
+		if l.Value.(*UIObject).id == id {
+			return l
+		}
+	}
+	return nil
+}
+
+// True if c is in a subtree that a copy made with CloneForWrite, which contains the element, shares
+// with its original.
+func (he *UIObject) sharesWithOriginal(c *UIObject) bool {
+	for p := he; p != nil; p = p.parent {
+		if p.sharedNodes == nil {
+			continue
+		}
+		for x := c; x != nil; x = x.parent {
+			if p.sharedNodes[x] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Check a new child can be added, and prepare it. The child is detached from any current parent.
+func (he *UIObject) adopt(ui HTMLElementWriter) (*UIObject, error) {
+	if c := toUIObject(ui); c.parent != nil && he.sharesWithOriginal(c) {
+		return nil, errorf(fmt.Sprintf("Can't move element %q", c.id), ErrShared)
+	}
+	if len(ui.Id()) == 0 {
+		ui.SetId(he.idGenerator().GenerateId(he, ui))
+	}
+	c := toUIObject(ui)
+	if x, ok := he.children[c.id]; ok && x != c {
+		return nil, errorf(fmt.Sprintf("Element %q already has a child with id %q", he.id, c.id), ErrDuplicateId)
+	}
+	if c.isAncestorOf(he) {
+		return nil, errorf(fmt.Sprintf("Can't add element %q to %q", c.id, he.id), ErrCycle)
+	}
+	c.DetachFromParent()
+	c.parent = he
+	he.children[c.id] = c
+	return c, nil
+}
+
+// True if the element is uio, or one of the ancestors of uio.
+func (he *UIObject) isAncestorOf(uio *UIObject) bool {
+	for p := uio; p != nil; p = p.parent {
+		if p == he {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove a child by id. The removed child, and its descendants, become a separate hierarchy.
+// Returns ErrNotFound if there is no child with the id.
+// Implements the Child Interface.
+func (he *UIObject) RemoveChild(id string) error {
+	l := he.childElement(id)
+	if l == nil {
+		return errorf(fmt.Sprintf("Element %q has no child with id %q", he.id, id), ErrNotFound)
+	}
+	c := he.childOrder.Remove(l).(*UIObject)
+	delete(he.children, id)
+	// A child shared with the original of a copy keeps its parent in the original.
+	if c.parent == he {
+		c.parent = nil
+	}
+	return nil
+}
+
+// Replace the child with the id by a new element, in the same position.
+// Returns ErrNotFound if there is no child with the id, or ErrDuplicateId if the new element's id
+// is already used by another child.
+// Implements the Child Interface.
+func (he *UIObject) ReplaceChild(id string, ui HTMLElementWriter) error {
+	l := he.childElement(id)
+	if l == nil {
+		return errorf(fmt.Sprintf("Element %q has no child with id %q", he.id, id), ErrNotFound)
+	}
+	old := l.Value.(*UIObject)
+	if toUIObject(ui) == old {
+		return nil
+	}
+	// The replaced child's id is free for the new element.
+	delete(he.children, id)
+	c, err := he.adopt(ui)
+	if err != nil {
+		he.children[id] = old
+		return err
+	}
+	l.Value = c
+	if old.parent == he {
+		old.parent = nil
+	}
+	return nil
+}
+
+// Insert a child at the index of the ordered children. An index equal to ChildCount() appends the child.
+// Returns ErrIndexRange if the index is invalid, or ErrDuplicateId if a child has the same id.
+// Implements the Child Interface.
+func (he *UIObject) InsertChildAt(index int, ui HTMLElementWriter) error {
+	if index < 0 || index > he.childOrder.Len() {
+		return errorf(fmt.Sprintf("Insert at %d in element %q with %d children", index, he.id, he.childOrder.Len()),
+			ErrIndexRange)
+	}
+	c, err := he.adopt(ui)
+	if err != nil {
+		return err
+	}
+	// The child may have been detached from this parent, so find the mark after adopting.
+	if index >= he.childOrder.Len() {
+		he.childOrder.PushBack(c)
+		return nil
+	}
+	l := he.childOrder.Front()
+	for i := 0; i < index; i++ {
+		l = l.Next()
+	}
+	he.childOrder.InsertBefore(c, l)
+	return nil
+}
+
+// Insert a child immediately after the child with the id afterId.
+// Returns ErrNotFound if there is no child afterId, or ErrDuplicateId if a child has the same id.
+// Implements the Child Interface.
+func (he *UIObject) InsertAfter(afterId string, ui HTMLElementWriter) error {
+	if he.childElement(afterId) == nil || toUIObject(ui).id == afterId {
+		return errorf(fmt.Sprintf("Element %q has no child with id %q", he.id, afterId), ErrNotFound)
+	}
+	c, err := he.adopt(ui)
+	if err != nil {
+		return err
+	}
+	he.childOrder.InsertAfter(c, he.childElement(afterId))
+	return nil
+}
+
+// Move the element, and its descendants, to the end of the children of newParent.
+// Returns ErrDuplicateId if newParent has a child with the same id, or ErrCycle if newParent is
+// the element or one of its descendants.
+// Implements the Child Interface.
+func (he *UIObject) MoveTo(newParent HTMLElementWriter) error {
+	p := toUIObject(newParent)
+	c, err := p.adopt(he)
+	if err != nil {
+		return err
+	}
+	p.childOrder.PushBack(c)
+	return nil
+}
+
+// Remove the element from its parent. If the element has no parent, nothing is changed.
+// Implements the Child Interface.
+func (he *UIObject) DetachFromParent() HTMLElementWriter {
+	p := he.parent
+	if p == nil {
+		return he
+	}
+	for l := p.childOrder.Front(); l != nil; l = l.Next() {
+		if l.Value.(*UIObject) == he {
+			p.childOrder.Remove(l)
+			break
+		}
+	}
+	if p.children[he.id] == he {
+		delete(p.children, he.id)
+	}
+	he.parent = nil
+	return he
+}
+
+// Return the parent of the element, or nil if the element is the root of a hierarchy.
+// Implements the Parent interface.
+func (he *UIObject) Parent() HTMLElementWriter {
+	if he.parent == nil {
+		return nil
+	}
+	return he.parent
+}
+
+// Return the ancestors of the element, starting with the parent and ending with the root.
+// Implements the Parent interface.
+func (he *UIObject) Ancestors() []HTMLElementWriter {
+	var x []HTMLElementWriter
+	for p := he.parent; p != nil; p = p.parent {
+		x = append(x, p)
+	}
+	return x
+}
+
+// Return the element, or nearest ancestor, with the content type. Returns nil if there is no match.
+// Example: submitBtn.Closest("form") finds the form containing a submit button.
+// Implements the Parent interface.
+func (he *UIObject) Closest(contentType string) HTMLElementWriter {
+	for p := he; p != nil; p = p.parent {
+		if p.contentType == contentType {
+			return p
+		}
+	}
+	return nil
+}
+
+// Return the chain of ids from the root of the hierarchy to the element, inclusive.
+// Implements the Parent interface.
+func (he *UIObject) Path() []string {
+	var x []string
+	for p := he; p != nil; p = p.parent {
+		x = append([]string{p.id}, x...)
+	}
+	return x
+}
@@ -0,0 +1,167 @@
+package goui
+
+import (
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func newTestMenu() *UIObject {
+	m := NewElement(ContentTypeMenu, "nav", "", "")
+	for _, id := range []string{"a", "b", "c"} {
+		m.AddChild(NewElement(ContentTypeLink, id, "", id))
+	}
+	return m
+}
+
+func childIds(uio *UIObject) []string {
+	ids := []string{}
+	for _, v := range uio.ChildrenByOrder() {
+		ids = append(ids, v.Id())
+	}
+	return ids
+}
+
+func assertTree(t *testing.T, uio *UIObject, ids []string) {
+	t.Helper()
+	x := childIds(uio)
+	gotestutil.AssertEqual(t, x, ids, "Unexpected children. Actual: %v.", x)
+	gotestutil.AssertEqual(t, len(uio.children), len(ids), "Expected %d children in map. Actual: %d.",
+		len(ids), len(uio.children))
+	for _, id := range ids {
+		gotestutil.AssertNotNil(t, uio.GetChildById(id), "Expected child %q in map.", id)
+	}
+}
+
+func TestUIObject_SetChildOrder(t *testing.T) {
+	m := newTestMenu()
+	m.SetChildOrder("c", "a")
+	assertTree(t, m, []string{"c", "a", "b"})
+	m.SetChildOrder("dummy", "a")
+	assertTree(t, m, []string{"c", "a", "b"})
+}
+
+func TestUIObject_SetIdChild(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		m := newTestMenu()
+		m.GetChildById("b").SetId("x")
+		assertTree(t, m, []string{"a", "x", "c"})
+	})
+	t.Run("B1", func(t *testing.T) {
+		m := newTestMenu()
+		m.GetChildById("b").SetId("a")
+		assertTree(t, m, []string{"a", "b", "c"})
+		gotestutil.AssertNil(t, m.RemoveChild("a"), "Expected nil error on RemoveChild.")
+		assertTree(t, m, []string{"b", "c"})
+	})
+}
+
+func TestUIObject_RemoveChild(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		m := newTestMenu()
+		err := m.RemoveChild("b")
+		gotestutil.AssertNil(t, err, "Expected nil error on RemoveChild.")
+		assertTree(t, m, []string{"a", "c"})
+	})
+	t.Run("B1", func(t *testing.T) {
+		m := newTestMenu()
+		err := m.RemoveChild("dummy")
+		gotestutil.AssertNotNil(t, err, "Expected error on RemoveChild for id \"dummy\".")
+		gotestutil.AssertTrue(t, err.(*Error).Err == ErrNotFound, "Expected ErrNotFound. Actual: %s.", err)
+	})
+}
+
+func TestUIObject_ReplaceChild(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		m := newTestMenu()
+		err := m.ReplaceChild("b", NewElement(ContentTypeLink, "x", "", "x"))
+		gotestutil.AssertNil(t, err, "Expected nil error on ReplaceChild.")
+		assertTree(t, m, []string{"a", "x", "c"})
+	})
+	t.Run("B1", func(t *testing.T) {
+		m := newTestMenu()
+		err := m.ReplaceChild("b", NewElement(ContentTypeLink, "c", "", "dup"))
+		gotestutil.AssertNotNil(t, err, "Expected error on ReplaceChild with duplicate id.")
+		assertTree(t, m, []string{"a", "b", "c"})
+	})
+}
+
+func TestUIObject_InsertChildAt(t *testing.T) {
+	m := newTestMenu()
+	gotestutil.AssertNil(t, m.InsertChildAt(0, NewElement(ContentTypeLink, "x", "", "")), "Expected nil error.")
+	gotestutil.AssertNil(t, m.InsertChildAt(4, NewElement(ContentTypeLink, "y", "", "")), "Expected nil error.")
+	assertTree(t, m, []string{"x", "a", "b", "c", "y"})
+	gotestutil.AssertNotNil(t, m.InsertChildAt(9, NewElement(ContentTypeLink, "z", "", "")),
+		"Expected error for index out of range.")
+}
+
+func TestUIObject_InsertAfter(t *testing.T) {
+	m := newTestMenu()
+	gotestutil.AssertNil(t, m.InsertAfter("a", NewElement(ContentTypeLink, "x", "", "")), "Expected nil error.")
+	assertTree(t, m, []string{"a", "x", "b", "c"})
+	gotestutil.AssertNil(t, m.InsertAfter("c", m.GetChildById("a")), "Expected nil error.")
+	assertTree(t, m, []string{"x", "b", "c", "a"})
+	gotestutil.AssertNotNil(t, m.InsertAfter("dummy", NewElement(ContentTypeLink, "y", "", "")),
+		"Expected error for id \"dummy\".")
+}
+
+func TestUIObject_MoveTo(t *testing.T) {
+	m := newTestMenu()
+	sub := NewElement(ContentTypeMenu, "sub", "", "")
+	m.AddChild(sub)
+	b := m.GetChildById("b")
+	gotestutil.AssertNil(t, b.MoveTo(sub), "Expected nil error on MoveTo.")
+	assertTree(t, m, []string{"a", "c", "sub"})
+	assertTree(t, sub, []string{"b"})
+
+	gotestutil.AssertNotNil(t, m.MoveTo(sub), "Expected error moving an element into its descendant.")
+	assertTree(t, m, []string{"a", "c", "sub"})
+}
+
+func TestUIObject_DetachFromParent(t *testing.T) {
+	m := newTestMenu()
+	a := m.GetChildById("a")
+	a.DetachFromParent()
+	assertTree(t, m, []string{"b", "c"})
+	a.DetachFromParent()
+	m.AddChild(a)
+	assertTree(t, m, []string{"b", "c", "a"})
+}
+
+func TestUIObject_Parent(t *testing.T) {
+	page, _ := NewElementFromJSON(testFormJSON)
+	ok := page.SearchChildrenById("ok")
+	gotestutil.AssertStringsEqual(t, ok.Parent().Id(), "grp", "Expected parent \"grp\". Actual: %s.", ok.Parent().Id())
+	gotestutil.AssertNil(t, page.Parent(), "Expected nil parent for the root.")
+
+	ok.MoveTo(page)
+	gotestutil.AssertStringsEqual(t, ok.Parent().Id(), "page", "Expected parent \"page\". Actual: %s.", ok.Parent().Id())
+	page.RemoveChild("ok")
+	gotestutil.AssertNil(t, ok.Parent(), "Expected nil parent after RemoveChild.")
+}
+
+func TestUIObject_Ancestors(t *testing.T) {
+	page, _ := NewElementFromJSON(testFormJSON)
+	x := queryIds(page.SearchChildrenById("ok").Ancestors())
+	gotestutil.AssertEqual(t, x, []string{"grp", "f1", "page"}, "Unexpected ancestors. Actual: %v.", x)
+}
+
+func TestUIObject_Closest(t *testing.T) {
+	page, _ := NewElementFromJSON(testFormJSON)
+	ok := page.SearchChildrenById("ok")
+	t.Run("A1", func(t *testing.T) {
+		f := ok.Closest("form")
+		gotestutil.AssertNotNil(t, f, "Expected valid enclosing form.")
+		gotestutil.AssertStringsEqual(t, f.Id(), "f1", "Expected form \"f1\". Actual: %s.", f.Id())
+		gotestutil.AssertStringsEqual(t, ok.Closest(ContentInputSubmit).Id(), "ok", "Expected the element itself.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		gotestutil.AssertNil(t, ok.Closest(ContentTypeMenu), "Expected nil for no enclosing menu.")
+	})
+}
+
+func TestUIObject_Path(t *testing.T) {
+	page, _ := NewElementFromJSON(testFormJSON)
+	x := page.SearchChildrenById("cancel").Path()
+	gotestutil.AssertEqual(t, x, []string{"page", "f1", "grp", "cancel"}, "Unexpected path. Actual: %v.", x)
+}
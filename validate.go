@@ -0,0 +1,336 @@
+package goui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Validation rules for form fields. The rules of a field are checked on the server by Form.Validate,
+// and the rules that HTML5 supports are also added as attributes, e.g. required or pattern, so the
+// browser checks them before the form is submitted.
+//
+// Example:
+//     form.AddChild(NewEmailInput("email").AddRule(RequiredRule(), EmailRule()))
+//     values, err := form.Bind(r)
+//     if errs := form.Validate(values); len(errs) > 0 {
+//         // Render the form again, with an error next to each invalid field.
+//     }
+
+// A week value is not in the format "2006-W01".
+var ErrInputWeek = errors.New("invalid week value")
+
+// A validation rule for the submitted values of a field.
+type Rule struct {
+	// The rule name, e.g. "required" or "pattern".
+	Name string
+	// The message for an invalid value. If empty, the message of the rule is used.
+	Message string
+	// HTML5 attributes for the rule, e.g. {"minlength": "3"}
+	Attrs AttributeMap
+	// Check a value. Returns a message if it's invalid, or "".
+	check func(v string) string
+	// Check empty values too. Other rules only check values that are not empty.
+	checkEmpty bool
+}
+
+// Return a copy of the rule with a different message.
+func (r Rule) WithMessage(msg string) Rule {
+	r.Message = msg
+	return r
+}
+
+// Check the submitted values of a field. A field with no values is checked as a single empty value.
+// Returns an error with the message for the first invalid value, or nil if they are all valid.
+func (r Rule) Validate(values []string) error {
+	if r.check == nil {
+		return nil
+	}
+	if len(values) == 0 {
+		values = []string{""}
+	}
+	for _, v := range values {
+		if len(strings.TrimSpace(v)) == 0 && !r.checkEmpty {
+			continue
+		}
+		if msg := r.check(v); len(msg) > 0 {
+			if len(r.Message) > 0 {
+				msg = r.Message
+			}
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+// A value is required. For a checkbox or radio group, one of them must be checked.
+func RequiredRule() Rule {
+	return Rule{Name: "required", Attrs: AttributeMap{"required": ""}, checkEmpty: true,
+		check: func(v string) string {
+			if len(strings.TrimSpace(v)) == 0 {
+				return "This field is required."
+			}
+			return ""
+		}}
+}
+
+// A value has at least n characters.
+func MinLengthRule(n int) Rule {
+	return Rule{Name: "minlength", Attrs: AttributeMap{"minlength": strconv.Itoa(n)},
+		check: func(v string) string {
+			if utf8.RuneCountInString(v) < n {
+				return fmt.Sprintf("Use at least %d characters.", n)
+			}
+			return ""
+		}}
+}
+
+// A value has at most n characters.
+func MaxLengthRule(n int) Rule {
+	return Rule{Name: "maxlength", Attrs: AttributeMap{"maxlength": strconv.Itoa(n)},
+		check: func(v string) string {
+			if utf8.RuneCountInString(v) > n {
+				return fmt.Sprintf("Use at most %d characters.", n)
+			}
+			return ""
+		}}
+}
+
+// A value matches the regular expression. As in the HTML pattern attribute, the whole value must
+// match, so the expression should use syntax that both Go and JavaScript support.
+// An invalid expression is logged, and the rule accepts every value.
+func PatternRule(re string) Rule {
+	r := Rule{Name: "pattern"}
+	x, err := regexp.Compile("^(?:" + re + ")$")
+	if err != nil {
+		log.Printf("goui.PatternRule: %s", err)
+		return r
+	}
+	r.Attrs = AttributeMap{"pattern": re}
+	r.check = func(v string) string {
+		if !x.MatchString(v) {
+			return "Enter a value in the requested format."
+		}
+		return ""
+	}
+	return r
+}
+
+// A value is an email address, e.g. "name@example.com", without a display name.
+func EmailRule() Rule {
+	return Rule{Name: "email", check: func(v string) string {
+		a, err := mail.ParseAddress(v)
+		if err != nil || a.Address != v || !strings.Contains(v, "@") {
+			return "Enter an email address."
+		}
+		return ""
+	}}
+}
+
+// A value is an absolute URL, e.g. "https://example.com/".
+func URLRule() Rule {
+	return Rule{Name: "url", check: func(v string) string {
+		u, err := url.ParseRequestURI(v)
+		if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+			return "Enter a URL."
+		}
+		return ""
+	}}
+}
+
+// A value is a number from min to max. Use math.Inf for an open end.
+func RangeRule(min, max float64) Rule {
+	attrs := make(AttributeMap, 2)
+	if !math.IsInf(min, 0) {
+		attrs["min"] = formatNumber(min)
+	}
+	if !math.IsInf(max, 0) {
+		attrs["max"] = formatNumber(max)
+	}
+	return Rule{Name: "range", Attrs: attrs,
+		check: func(v string) string {
+			n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			switch {
+			case err != nil:
+				return "Enter a number."
+			case n < min:
+				return fmt.Sprintf("Enter a number no less than %s.", formatNumber(min))
+			case n > max:
+				return fmt.Sprintf("Enter a number no more than %s.", formatNumber(max))
+			}
+			return ""
+		}}
+}
+
+// A value is a time from from to to, in the value format of a date, month, week, time or
+// datetime-local input. A zero time leaves that end open.
+func DateRangeRule(contentType string, from, to time.Time) Rule {
+	r := Rule{Name: "daterange", Attrs: make(AttributeMap, 1)}
+	var min, max time.Time
+	if !from.IsZero() {
+		r.Attrs["min"] = FormatInputTime(contentType, from)
+		min, _ = ParseInputTime(contentType, r.Attrs["min"])
+	}
+	if !to.IsZero() {
+		r.Attrs["max"] = FormatInputTime(contentType, to)
+		max, _ = ParseInputTime(contentType, r.Attrs["max"])
+	}
+	r.check = func(v string) string {
+		t, err := ParseInputTime(contentType, strings.TrimSpace(v))
+		switch {
+		case err != nil:
+			return "Enter a valid date."
+		case !from.IsZero() && t.Before(min):
+			return fmt.Sprintf("Enter a date no earlier than %s.", r.Attrs["min"])
+		case !to.IsZero() && t.After(max):
+			return fmt.Sprintf("Enter a date no later than %s.", r.Attrs["max"])
+		}
+		return ""
+	}
+	return r
+}
+
+// A custom rule. The function returns an error, with the message for the user, if a value is invalid.
+func FuncRule(name string, fn func(v string) error) Rule {
+	return Rule{Name: name, check: func(v string) string {
+		if err := fn(v); err != nil {
+			return err.Error()
+		}
+		return ""
+	}}
+}
+
+// Parse a value in the format of the input type, e.g. "2017-W01" for a week input. The result is UTC.
+func ParseInputTime(contentType, v string) (time.Time, error) {
+	switch contentType {
+	case ContentInputMonth:
+		return time.Parse("2006-01", v)
+	case ContentInputWeek:
+		var y, w int
+		if n, err := fmt.Sscanf(v, "%4d-W%2d", &y, &w); err != nil || n != 2 || w < 1 || w > 53 {
+			return time.Time{}, errorf(fmt.Sprintf("Week %q", v), ErrInputWeek)
+		}
+		// Week 1 is the week with January 4th in it, and weeks start on Monday.
+		jan4 := time.Date(y, 1, 4, 0, 0, 0, 0, time.UTC)
+		return jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(w-1)*7), nil
+	case ContentInputTime:
+		return time.Parse("15:04", v)
+	case ContentInputDateTimeLoc:
+		return time.Parse("2006-01-02T15:04", v)
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+// Add validation rules to a form field. The HTML5 attributes of the rules are added, if the
+// element type allows them.
+// Implements the Validation interface
+func (he *UIObject) AddRule(rules ...Rule) HTMLElementWriter {
+	for _, r := range rules {
+		he.rules = append(he.rules, r)
+		for k, v := range r.Attrs {
+			if ruleAttributeAllowed(he.contentType, k) {
+				he.AddAttribute(k, v)
+			}
+		}
+	}
+	return he
+}
+
+// Reports whether a rule attribute is valid on an element of the content type: the input type
+// attributes, see InputAttributeAllowed, required, minlength and maxlength on a textarea, and
+// required on a select. Global, data-* and aria-* attributes are valid on every element.
+func ruleAttributeAllowed(contentType, name string) bool {
+	name = strings.ToLower(name)
+	switch tagForContentType(contentType).name {
+	case "input":
+		return InputAttributeAllowed(contentType, name)
+	case "textarea":
+		if name == "required" || name == "minlength" || name == "maxlength" {
+			return true
+		}
+	case "select":
+		if name == "required" {
+			return true
+		}
+	}
+	return globalAttributes[name] || strings.HasPrefix(name, dataPrefix) || strings.HasPrefix(name, ariaPrefix)
+}
+
+// Return the validation rules of the element.
+// Implements the Validation interface
+func (he *UIObject) Rules() []Rule {
+	return append([]Rule(nil), he.rules...)
+}
+
+// A failed validation rule for a field.
+type FieldError struct {
+	// The field name
+	Name string `json:"name"`
+	// The name of the rule that failed.
+	Rule string `json:"rule"`
+	// The message for the user.
+	Message string `json:"message"`
+}
+
+// Validation errors, keyed by the element id of the field.
+type ValidationErrors map[string]FieldError
+
+func (ve ValidationErrors) Error() string {
+	x := make([]string, 0, len(ve))
+	for id, fe := range ve {
+		x = append(x, id+": "+fe.Message)
+	}
+	sort.Strings(x)
+	return strings.Join(x, "; ")
+}
+
+// Check the submitted values, e.g. from Bind, against the rules of each field. The first failed rule
+// of a field is returned, keyed by the element id, and is shown next to the field with SetFieldError.
+// The values of a file input are the names of the files uploaded by the last Bind, so RequiredRule
+// checks that a file was uploaded. Earlier field errors are cleared. Returns nil if all values are
+// valid.
+func (f *Form) Validate(values url.Values) ValidationErrors {
+	f.ClearErrors()
+	var errs ValidationErrors
+	var invalid []*UIObject
+	f.walk(func(uio *UIObject, depth int) WalkAction {
+		if !isFormField(uio) {
+			return WalkContinue
+		}
+		name := uio.attrs["name"]
+		v := values[name]
+		if uio.contentType == ContentInputFile {
+			v = nil
+			for _, fh := range f.files[name] {
+				v = append(v, fh.Filename)
+			}
+		}
+		for _, r := range uio.rules {
+			if err := r.Validate(v); err != nil {
+				if errs == nil {
+					errs = make(ValidationErrors, 1)
+				}
+				errs[uio.id] = FieldError{Name: name, Rule: r.Name, Message: err.Error()}
+				invalid = append(invalid, uio)
+				break
+			}
+		}
+		return WalkContinue
+	}, 0)
+	// Add the messages after the walk, since they change the children of the walked elements.
+	for _, uio := range invalid {
+		if err := f.SetFieldError(uio.attrs["name"], errs[uio.id].Message); err != nil {
+			log.Printf("goui.Validate: %s", err)
+		}
+	}
+	return errs
+}
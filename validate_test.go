@@ -0,0 +1,93 @@
+package goui
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestRuleValidate(t *testing.T) {
+	t.Run("A1", func(t *testing.T) {
+		gotestutil.AssertNotNil(t, RequiredRule().Validate(nil), "Expected required to fail with no value.")
+		gotestutil.AssertNotNil(t, RequiredRule().Validate([]string{" "}), "Expected required to fail on spaces.")
+		gotestutil.AssertNil(t, MinLengthRule(3).Validate([]string{""}), "Expected empty value to skip minlength.")
+		gotestutil.AssertNotNil(t, MinLengthRule(3).Validate([]string{"ab"}), "Expected minlength to fail.")
+		gotestutil.AssertNil(t, MaxLengthRule(3).Validate([]string{"äöü"}), "Expected maxlength to count runes.")
+		gotestutil.AssertNil(t, PatternRule(`[a-z]+`).Validate([]string{"abc"}), "Expected pattern to match.")
+		gotestutil.AssertNotNil(t, PatternRule(`[a-z]+`).Validate([]string{"abc1"}), "Expected pattern to match all.")
+		gotestutil.AssertNil(t, EmailRule().Validate([]string{"a@b.c"}), "Expected valid email.")
+		gotestutil.AssertNotNil(t, EmailRule().Validate([]string{"A <a@b.c>"}), "Expected display name refused.")
+		gotestutil.AssertNil(t, URLRule().Validate([]string{"https://example.com/x"}), "Expected valid URL.")
+		gotestutil.AssertNotNil(t, URLRule().Validate([]string{"/x"}), "Expected relative URL refused.")
+		gotestutil.AssertNotNil(t, RangeRule(1, 5).Validate([]string{"6"}), "Expected range to fail.")
+		gotestutil.AssertNotNil(t, RangeRule(1, 5).Validate([]string{"x"}), "Expected range to fail on text.")
+	})
+	t.Run("B1", func(t *testing.T) {
+		from := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+		r := DateRangeRule(ContentInputWeek, from, time.Time{})
+		gotestutil.AssertStringsEqual(t, r.Attrs["min"], "2016-W52", "Unexpected week min.")
+		gotestutil.AssertNil(t, r.Validate([]string{"2017-W01"}), "Expected week in range.")
+		gotestutil.AssertNotNil(t, r.Validate([]string{"2016-W51"}), "Expected week before min.")
+		gotestutil.AssertNotNil(t, r.Validate([]string{"2017-01"}), "Expected invalid week.")
+
+		c := FuncRule("even", func(v string) error {
+			if len(v)%2 != 0 {
+				return errors.New("Use an even number of characters.")
+			}
+			return nil
+		}).WithMessage("Odd.")
+		err := c.Validate([]string{"abc"})
+		gotestutil.AssertTrue(t, err != nil && err.Error() == "Odd.", "Expected custom message. %v", err)
+	})
+}
+
+func TestAddRule(t *testing.T) {
+	in := NewNumberInput("qty").AddRule(RequiredRule(), RangeRule(1, 10), MinLengthRule(1))
+	x := renderString(t, toUIObject(in))
+	gotestutil.AssertStringsEqual(t, x, `<input type="number" id="qty" max="10" min="1" name="qty" required>`,
+		"Unexpected input HTML, minlength isn't allowed on number inputs. Actual: %s.", x)
+	gotestutil.AssertEqual(t, len(in.Rules()), 3, "Expected all rules kept.")
+	gotestutil.AssertEqual(t, len(in.Clone().Rules()), 3, "Expected rules copied by Clone.")
+
+	s := NewSelect("size").AddRule(RequiredRule(), PatternRule("[SML]"), RangeRule(1, 3))
+	x = renderString(t, toUIObject(s))
+	gotestutil.AssertStringsEqual(t, x, `<select id="size" name="size" required></select>`,
+		"Expected only required on a select. Actual: %s.", x)
+	ta := NewTextarea("bio").AddRule(MinLengthRule(3), PatternRule("[a-z]+"))
+	x = renderString(t, toUIObject(ta))
+	gotestutil.AssertStringsEqual(t, x, `<textarea id="bio" minlength="3" name="bio"></textarea>`,
+		"Expected no pattern on a textarea. Actual: %s.", x)
+}
+
+func TestFormValidate(t *testing.T) {
+	f := NewForm("signup", "/signup", "post")
+	f.AddChild(NewEmailInput("email").AddRule(RequiredRule(), EmailRule()))
+	f.AddChild(NewTextInput("user").AddRule(MinLengthRule(3)))
+	f.AddChild(NewRadioInput("size", "S").AddRule(RequiredRule()))
+	f.AddChild(NewRadioInput("size", "L"))
+
+	errs := f.Validate(url.Values{"email": {"nope"}, "user": {"bob"}})
+	gotestutil.AssertEqual(t, len(errs), 2, "Expected two invalid fields. %v", errs)
+	gotestutil.AssertStringsEqual(t, errs["email"].Rule, "email", "Expected email rule to fail.")
+	gotestutil.AssertStringsEqual(t, errs["size-S"].Rule, "required", "Expected required radio to fail.")
+	gotestutil.AssertStringsEqual(t, strings.Join(childIds(f.UIObject), ","),
+		"email,email-error,user,size-S,size-L,size-L-error", "Expected field errors after the fields.")
+	gotestutil.AssertStringsEqual(t, f.FieldErrors()["email"], "Enter an email address.", "Unexpected email message.")
+
+	errs = f.Validate(url.Values{"email": {"a@b.c"}, "size": {"L"}})
+	gotestutil.AssertNil(t, errs, "Expected no errors. %v", errs)
+	gotestutil.AssertEqual(t, f.ChildCount(), 4, "Expected field errors removed.")
+
+	up := NewForm("up", "/up", "post").Multipart()
+	up.AddChild(NewFileInput("photo").AddRule(RequiredRule()))
+	errs = up.Validate(url.Values{"photo": {"cat.png"}})
+	gotestutil.AssertStringsEqual(t, errs["photo"].Rule, "required", "Expected required to check the uploaded files.")
+	up.files = map[string][]*multipart.FileHeader{"photo": {{Filename: "cat.png"}}}
+	errs = up.Validate(nil)
+	gotestutil.AssertNil(t, errs, "Expected an uploaded file to be valid. %v", errs)
+}
@@ -0,0 +1,118 @@
+package goui
+
+import (
+	"reflect"
+)
+
+// Traversal of an element tree in the ChildrenByOrder sequence.
+//
+
+// The value returned by a Walk function to control the traversal.
+type WalkAction int
+
+const (
+	// Continue with the children of the element, then the following elements.
+	WalkContinue WalkAction = iota
+	// Don't visit the children of the element. Ignored by WalkPostOrder, which visits children first.
+	WalkSkipChildren
+	// End the traversal.
+	WalkStop
+)
+
+// Visit the element and all descendants, in pre-order: each element before its children, and children
+// in order. The depth of the element the walk started from is zero.
+// Example: counting the links in a menu, without the submenus
+//
+//	n := 0
+//	menu.Walk(func(el HTMLElementWriter, depth int) WalkAction {
+//	    if depth > 0 && el.ContentType() == ContentTypeMenu {
+//	        return WalkSkipChildren
+//	    }
+//	    if el.ContentType() == ContentTypeLink {
+//	        n++
+//	    }
+//	    return WalkContinue
+//	})
+//
+// Implements the HTMLElementWriter interface
+func (he *UIObject) Walk(fn func(el HTMLElementWriter, depth int) WalkAction) {
+	he.walk(func(uio *UIObject, depth int) WalkAction {
+		return fn(uio, depth)
+	}, 0)
+}
+
+// Visit the element and all descendants, in post-order: the children of an element, in order, before
+// the element itself. Returning WalkStop ends the traversal.
+// Implements the HTMLElementWriter interface
+func (he *UIObject) WalkPostOrder(fn func(el HTMLElementWriter, depth int) WalkAction) {
+	he.walkPost(func(uio *UIObject, depth int) WalkAction {
+		return fn(uio, depth)
+	}, 0)
+}
+
+// Rewrite the tree, bottom up. The function is called for each element after its children are
+// transformed. If it returns a different element, that element replaces the original; if it returns
+// nil, or a nil pointer such as (*UIObject)(nil), the element is removed. Returns the result for the
+// element Transform was called on, which is the new root of the tree, or nil.
+// Implements the HTMLElementWriter interface
+func (he *UIObject) Transform(fn func(el HTMLElementWriter) HTMLElementWriter) HTMLElementWriter {
+	for _, c := range he.ChildrenByOrder() {
+		uio := toUIObject(c)
+		x := uio.Transform(fn)
+		switch {
+		case isNilElement(x):
+			he.RemoveChild(uio.id)
+		case toUIObject(x) != uio:
+			if err := he.ReplaceChild(uio.id, x); err != nil {
+				logMsg("Transform, unable to replace element.", map[string]string{
+					"error": err.Error(), "id": uio.id})
+			}
+		}
+	}
+	if x := fn(he); !isNilElement(x) {
+		return x
+	}
+	return nil
+}
+
+// True if the element is nil, a nil pointer, or a type embedding a nil *UIObject.
+func isNilElement(ui HTMLElementWriter) bool {
+	if ui == nil {
+		return true
+	}
+	if v := reflect.ValueOf(ui); v.Kind() == reflect.Ptr && v.IsNil() {
+		return true
+	}
+	return toUIObject(ui) == nil
+}
+
+// Pre-order traversal over *UIObject. Returns false if the walk was stopped.
+func (he *UIObject) walk(fn func(uio *UIObject, depth int) WalkAction, depth int) bool {
+	switch fn(he, depth) {
+	case WalkStop:
+		return false
+	case WalkSkipChildren:
+		return true
+	}
+	for l := he.childOrder.Front(); l != nil; {
+		// Get the next element first, in case fn removes this one.
+		next := l.Next()
+		if !l.Value.(*UIObject).walk(fn, depth+1) {
+			return false
+		}
+		l = next
+	}
+	return true
+}
+
+// Post-order traversal over *UIObject. Returns false if the walk was stopped.
+func (he *UIObject) walkPost(fn func(uio *UIObject, depth int) WalkAction, depth int) bool {
+	for l := he.childOrder.Front(); l != nil; {
+		next := l.Next()
+		if !l.Value.(*UIObject).walkPost(fn, depth+1) {
+			return false
+		}
+		l = next
+	}
+	return fn(he, depth) != WalkStop
+}
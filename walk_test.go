@@ -0,0 +1,92 @@
+package goui
+
+import (
+	"testing"
+
+	"github.com/mooredwightd/gotestutil"
+)
+
+func TestUIObject_Walk(t *testing.T) {
+	page, _ := NewElementFromJSON(testFormJSON)
+
+	t.Run("A1", func(t *testing.T) {
+		var ids []string
+		var depths []int
+		page.Walk(func(el HTMLElementWriter, depth int) WalkAction {
+			ids = append(ids, el.Id())
+			depths = append(depths, depth)
+			return WalkContinue
+		})
+		gotestutil.AssertEqual(t, ids, []string{"page", "f1", "email", "grp", "ok", "cancel", "save", "f2", "other"},
+			"Unexpected walk order. Actual: %v.", ids)
+		gotestutil.AssertEqual(t, depths, []int{0, 1, 2, 2, 3, 3, 2, 1, 2}, "Unexpected depths. Actual: %v.", depths)
+	})
+
+	t.Run("A2", func(t *testing.T) {
+		var ids []string
+		page.Walk(func(el HTMLElementWriter, depth int) WalkAction {
+			ids = append(ids, el.Id())
+			switch el.Id() {
+			case "grp":
+				return WalkSkipChildren
+			case "save":
+				return WalkStop
+			}
+			return WalkContinue
+		})
+		gotestutil.AssertEqual(t, ids, []string{"page", "f1", "email", "grp", "save"},
+			"Unexpected walk with pruning. Actual: %v.", ids)
+	})
+}
+
+func TestUIObject_WalkPostOrder(t *testing.T) {
+	page, _ := NewElementFromJSON(testFormJSON)
+	var ids []string
+	page.WalkPostOrder(func(el HTMLElementWriter, depth int) WalkAction {
+		ids = append(ids, el.Id())
+		if el.Id() == "f1" {
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	gotestutil.AssertEqual(t, ids, []string{"email", "ok", "cancel", "grp", "save", "f1"},
+		"Unexpected post-order walk. Actual: %v.", ids)
+}
+
+func TestUIObject_Transform(t *testing.T) {
+	page, _ := NewElementFromJSON(testFormJSON)
+	root := page.Transform(func(el HTMLElementWriter) HTMLElementWriter {
+		switch el.ContentType() {
+		case ContentInputReset:
+			return nil
+		case ContentInputSubmit:
+			return NewElement("button", el.Id(), el.Class(), "Submit")
+		}
+		return el
+	})
+	gotestutil.AssertTrue(t, root == HTMLElementWriter(page), "Expected the same root element.")
+	gotestutil.AssertNil(t, page.SearchChildrenById("cancel"), "Expected element \"cancel\" removed.")
+	x := queryIds(page.Query("button"))
+	gotestutil.AssertEqual(t, x, []string{"ok", "save", "other"}, "Unexpected transformed elements. Actual: %v.", x)
+	gotestutil.AssertStringsEqual(t, page.SearchChildrenById("ok").Parent().Id(), "grp", "Expected parent \"grp\".")
+
+	t.Run("B1", func(t *testing.T) {
+		page, _ := NewElementFromJSON(testFormJSON)
+		root := page.Transform(func(el HTMLElementWriter) HTMLElementWriter {
+			switch el.Id() {
+			case "cancel":
+				return (*UIObject)(nil)
+			case "f2":
+				return (*Form)(nil)
+			case "save":
+				return &Form{}
+			case "page":
+				return (*UIObject)(nil)
+			}
+			return el
+		})
+		gotestutil.AssertTrue(t, root == nil, "Expected a nil root for a typed nil.")
+		x := queryIds(page.Query("*"))
+		gotestutil.AssertEqual(t, x, []string{"f1", "email", "grp", "ok"}, "Expected typed nils removed. Actual: %v.", x)
+	})
+}